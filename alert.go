@@ -1,6 +1,14 @@
 package alertmanager
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidAlert is returned in dry-run mode when an alert would be rejected by Alertmanager.
+var ErrInvalidAlert = errors.New("invalid alert")
 
 // Alert is an Alertmanager alert.
 type Alert struct {
@@ -16,6 +24,13 @@ type Alert struct {
 	// EndsAt is the time the alert should be considered resolved.
 	// If omitted, the alert will be resolved after the global resolve_timeout.
 	EndsAt *time.Time `json:"endsAt,omitempty"`
+
+	// labelTemplates and annotationTemplates are rendered into Labels and Annotations when the alert is emitted.
+	labelTemplates      map[string]string
+	annotationTemplates map[string]string
+
+	// value is available as $value in templates.
+	value float64
 }
 
 // AlertOption is a functional option for configuring an Alert.
@@ -68,3 +83,22 @@ func WithEndsAt(t time.Time) AlertOption {
 		a.EndsAt = &t
 	}
 }
+
+// validate checks that Alertmanager would accept the alert.
+func (a *Alert) validate() error {
+	if len(a.Labels) == 0 {
+		return fmt.Errorf("%w: at least one label required", ErrInvalidAlert)
+	}
+	for name, value := range a.Labels {
+		if name == "" || !utf8.ValidString(name) {
+			return fmt.Errorf("%w: invalid label name %q", ErrInvalidAlert, name)
+		}
+		if !utf8.ValidString(value) {
+			return fmt.Errorf("%w: invalid value of label %s", ErrInvalidAlert, name)
+		}
+	}
+	if a.StartsAt != nil && a.EndsAt != nil && a.EndsAt.Before(*a.StartsAt) {
+		return fmt.Errorf("%w: end time %s is before start time %s", ErrInvalidAlert, a.EndsAt, a.StartsAt)
+	}
+	return nil
+}
@@ -2,16 +2,21 @@ package alertmanager
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -23,6 +28,36 @@ var (
 
 	// ErrNilHTTPClient is returned when a nil HTTP client is provided.
 	ErrNilHTTPClient = errors.New("HTTP client cannot be nil")
+
+	// ErrNilLogger is returned when a nil logger is provided.
+	ErrNilLogger = errors.New("logger cannot be nil")
+
+	// ErrNilDialer is returned when a nil dial function is provided.
+	ErrNilDialer = errors.New("dial function cannot be nil")
+
+	// ErrUnixSocketRequired is returned when an empty Unix socket path is provided.
+	ErrUnixSocketRequired = errors.New("invalid Alertmanager config: Unix socket path required")
+
+	// ErrNilRoundTripper is returned when nil RoundTripper middleware is provided.
+	ErrNilRoundTripper = errors.New("RoundTripper middleware cannot be nil")
+
+	// ErrUnsupportedTransport is returned when TLS, proxy or dialer options are used with an HTTP client
+	// whose transport is not an *http.Transport. Use WithRoundTripper to add custom RoundTrippers instead.
+	ErrUnsupportedTransport = errors.New("invalid Alertmanager config: transport options require a nil or *http.Transport HTTP client transport")
+
+	// ErrInvalidProxyURL is returned when a proxy URL is invalid.
+	ErrInvalidProxyURL = errors.New("invalid Alertmanager config: proxy URL scheme and host are required")
+
+	// ErrConflictingProxy is returned when both a proxy URL and proxy from environment are configured.
+	ErrConflictingProxy = errors.New("invalid Alertmanager config: proxy URL and proxy from environment are mutually exclusive")
+)
+
+// Structured logging keys used consistently by the Alertmanager client across logger backends.
+const (
+	logKeyEndpoint   = "endpoint"
+	logKeyAlertCount = "alert_count"
+	logKeyStatus     = "status"
+	logKeyPayload    = "payload"
 )
 
 // Args contains client configuration for Alertmanager.
@@ -39,6 +74,14 @@ type Args struct {
 	// Password is the password for basic authentication (optional)
 	Password string
 
+	// PasswordFile is the path to a file containing the password for basic authentication (optional)
+	// It is read again whenever it changes; mutually exclusive with Password
+	PasswordFile string
+
+	// BearerTokenFile is the path to a file containing a bearer token (optional)
+	// It is read again whenever it changes; mutually exclusive with basic authentication
+	BearerTokenFile string
+
 	// TLSCACertPath is the path to the TLS CA certificate (optional)
 	TLSCACertPath string
 
@@ -54,6 +97,23 @@ type Args struct {
 	// Timeout is the timeout for HTTP requests to Alertmanager
 	// If not specified, a default of 2 seconds is used
 	Timeout time.Duration
+
+	// PathPrefix is the path under which the Alertmanager API is served (optional),
+	// e.g. the value of --web.route-prefix or an ingress subpath
+	PathPrefix string
+
+	// ProxyURL is the URL of an HTTP(S) proxy used to reach Alertmanager (optional)
+	// Credentials in the URL are sent to the proxy using basic authentication
+	ProxyURL string
+
+	// NoProxy is a comma-separated list of hosts that bypass ProxyURL (optional)
+	NoProxy string
+
+	// ProxyFromEnvironment uses the proxy configured by HTTP_PROXY, HTTPS_PROXY and NO_PROXY (optional)
+	ProxyFromEnvironment bool
+
+	// DryRun logs the alerts that would be sent instead of sending them (optional)
+	DryRun bool
 }
 
 // FlagBinder is an interface satisfied by both flag.FlagSet and pflag.FlagSet
@@ -69,11 +129,156 @@ func (a *Args) BindFlags(fb FlagBinder) {
 	fb.StringVar(&a.AlertmanagerURL, "alertmanager-url", "", "Alertmanager URL for sending alerts")
 	fb.StringVar(&a.Username, "alertmanager-username", "", "Alertmanager basic auth username")
 	fb.StringVar(&a.Password, "alertmanager-password", "", "Alertmanager basic auth password")
+	fb.StringVar(&a.PasswordFile, "alertmanager-password-file", "", "Path to a file containing the Alertmanager basic auth password")
+	fb.StringVar(&a.BearerTokenFile, "alertmanager-bearer-token-file", "", "Path to a file containing an Alertmanager bearer token")
 	fb.StringVar(&a.TLSCACertPath, "alertmanager-ca-cert-path", "", "Path to Alertmanager TLS CA certificate")
 	fb.BoolVar(&a.TLSInsecureSkipVerify, "alertmanager-tls-insecure", false, "Skip Alertmanager TLS certificate verification")
 	fb.StringVar(&a.TLSMinVersion, "alertmanager-tls-min-version", "", "Minimum TLS version for Alertmanager (TLS12, TLS13)")
 	fb.StringVar(&a.TLSMaxVersion, "alertmanager-tls-max-version", "", "Maximum TLS version for Alertmanager (TLS12, TLS13)")
 	fb.DurationVar(&a.Timeout, "alertmanager-timeout", 0, "Timeout for Alertmanager requests (default 2s)")
+	fb.StringVar(&a.PathPrefix, "alertmanager-path-prefix", "", "Path prefix of the Alertmanager API, e.g. its --web.route-prefix")
+	fb.StringVar(&a.ProxyURL, "alertmanager-proxy-url", "", "HTTP(S) proxy URL for Alertmanager requests")
+	fb.StringVar(&a.NoProxy, "alertmanager-no-proxy", "", "Comma-separated list of hosts that bypass the Alertmanager proxy")
+	fb.BoolVar(&a.ProxyFromEnvironment, "alertmanager-proxy-from-environment", false, "Use the proxy configured by HTTP_PROXY, HTTPS_PROXY and NO_PROXY")
+	fb.BoolVar(&a.DryRun, "alertmanager-dry-run", false, "Log the alerts that would be sent to Alertmanager instead of sending them")
+}
+
+// Validate checks the Args for missing, conflicting and invalid fields without creating a client,
+// e.g. in admission webhooks. The URL is only required if Enabled is set; all other fields are always
+// checked. Files are checked to be readable, and the CA certificate to contain a PEM certificate.
+// All problems are returned at once as a joined error, each prefixed by the name of its field.
+//
+// Validate is stricter than NewAlertmanagerWithArgs, which only logs a warning for an URL scheme other
+// than http or https, a CA certificate combined with TLSInsecureSkipVerify and a CA file without
+// PEM certificates, since such Args were accepted before Validate was added.
+func (a Args) Validate() error {
+	errs, warnings := a.validate()
+	return errors.Join(append(errs, warnings...)...)
+}
+
+// validate returns the problems of the Args that NewAlertmanagerWithArgs rejects,
+// and separately those that it only warns about.
+func (a Args) validate() (errs, warnings []error) {
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Errorf(format, args...))
+	}
+
+	switch {
+	case a.AlertmanagerURL == "":
+		if a.Enabled {
+			add("AlertmanagerURL: required when Enabled is set")
+		}
+	default:
+		// the url.Error of a failed parse is not wrapped, since it contains the URL with its credentials
+		if u, err := url.Parse(a.AlertmanagerURL); err != nil || u.Host == "" {
+			add("AlertmanagerURL: must be an http or https URL with a host, got %q: %w", redactURL(a.AlertmanagerURL), ErrInvalidEndpoint)
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			warn("AlertmanagerURL: must be an http or https URL with a host, got %q: %w", redactURL(a.AlertmanagerURL), ErrInvalidEndpoint)
+		}
+	}
+
+	if a.Timeout < 0 {
+		add("Timeout: must not be negative, got %s", a.Timeout)
+	}
+
+	switch {
+	case a.Password != "" && a.PasswordFile != "":
+		add("Password: mutually exclusive with PasswordFile")
+	case a.Username != "" && a.Password == "" && a.PasswordFile == "" && a.BearerTokenFile == "":
+		add("Username: requires Password or PasswordFile")
+	case a.Username == "" && a.Password != "":
+		add("Password: requires Username")
+	case a.Username == "" && a.PasswordFile != "":
+		add("PasswordFile: requires Username")
+	}
+	if a.BearerTokenFile != "" && a.Username != "" {
+		add("BearerTokenFile: mutually exclusive with basic auth")
+	}
+	if a.PasswordFile != "" {
+		if err := checkReadable(a.PasswordFile); err != nil {
+			add("PasswordFile: %w", err)
+		}
+	}
+	if a.BearerTokenFile != "" {
+		if err := checkReadable(a.BearerTokenFile); err != nil {
+			add("BearerTokenFile: %w", err)
+		}
+	}
+
+	if a.TLSCACertPath != "" {
+		if a.TLSInsecureSkipVerify {
+			warn("TLSCACertPath: mutually exclusive with TLSInsecureSkipVerify, which disables certificate verification")
+		}
+		if caCert, err := os.ReadFile(a.TLSCACertPath); err != nil {
+			add("TLSCACertPath: %w", err)
+		} else if !x509.NewCertPool().AppendCertsFromPEM(caCert) {
+			warn("TLSCACertPath: no PEM certificates found in %s", a.TLSCACertPath)
+		}
+	}
+
+	errs = append(errs, validateTLSVersions("TLSMinVersion", a.TLSMinVersion, "TLSMaxVersion", a.TLSMaxVersion)...)
+	errs = append(errs, validateProxy("ProxyURL", a.ProxyURL, a.ProxyFromEnvironment, "NoProxy", a.NoProxy)...)
+
+	return errs, warnings
+}
+
+// validateTLSVersions checks that the TLS versions are allowed and form a consistent range.
+// Problems are prefixed by the given field names, which are shared by Args and Config.
+func validateTLSVersions(minField, minVersion, maxField, maxVersion string) []error {
+	var errs []error
+	minV, minErr := stringToSecureTLSVersion(minVersion)
+	if minVersion != "" && minErr != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", minField, minErr))
+	}
+	maxV, maxErr := stringToSecureTLSVersion(maxVersion)
+	if maxVersion != "" && maxErr != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", maxField, maxErr))
+	}
+	if minVersion != "" && maxVersion != "" && minErr == nil && maxErr == nil && minV > maxV {
+		errs = append(errs, fmt.Errorf("%s: %s is greater than %s %s", minField, minVersion, maxField, maxVersion))
+	}
+	return errs
+}
+
+// validateProxy checks that the proxy URL is absolute and not combined with the proxy from the
+// environment, and that no proxy hosts are only set together with a proxy URL.
+func validateProxy(proxyField, proxyURL string, fromEnvironment bool, noProxyField, noProxy string) []error {
+	var errs []error
+	if proxyURL == "" {
+		if noProxy != "" {
+			errs = append(errs, fmt.Errorf("%s: requires %s", noProxyField, proxyField))
+		}
+		return errs
+	}
+
+	if u, err := url.Parse(proxyURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("%s: must be an absolute URL, got %q: %w", proxyField, redactURL(proxyURL), ErrInvalidProxyURL))
+	}
+	if fromEnvironment {
+		errs = append(errs, fmt.Errorf("%s: %w", proxyField, ErrConflictingProxy))
+	}
+	return errs
+}
+
+// checkReadable returns an error if the file at path cannot be opened for reading.
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
 }
 
 // Alertmanager represents the Alertmanager client.
@@ -81,9 +286,50 @@ type Alertmanager struct {
 	client *http.Client
 	log    logr.Logger
 
+	// transport is the base transport configured by TLS, proxy and dialer options;
+	// middleware is layered on top of it once all options have been applied
+	transport  *http.Transport
+	middleware []func(http.RoundTripper) http.RoundTripper
+
 	endpoint   string
 	authHeader string
 
+	// displayURL is the base URL and path prefix without credentials, used in metrics, logs and traces
+	displayURL string
+
+	// authFile is read for every request and formatted into the Authorization header by authFormat,
+	// so that rotated credentials are used; it takes the place of authHeader when set
+	authFile   *secretFile
+	authFormat func(secret string) string
+
+	// baseURL is the scheme and host of the endpoint; pathPrefix is prepended to all API paths
+	baseURL    string
+	pathPrefix string
+
+	// proxy settings applied to the HTTP transport
+	proxyURL             *url.URL
+	noProxy              string
+	proxyFromEnvironment bool
+
+	// metrics records sent alerts and request latencies (optional)
+	metrics *Metrics
+
+	// tracing of API requests and linking of alerts to the trace of the emitting context
+	tracerProvider   trace.TracerProvider
+	propagator       propagation.TextMapPropagator
+	traceLabels      *traceLink
+	traceAnnotations *traceLink
+
+	// tenantID is sent in tenantHeader with every request unless overridden by the request context
+	tenantID     string
+	tenantHeader string
+
+	// dryRun writes payloads instead of sending them when set (optional)
+	dryRun *dryRun
+
+	// sensitiveKeys are label and annotation keys whose values are redacted in logs
+	sensitiveKeys map[string]bool
+
 	// base labels and annotations to be applied to all alerts created by this Alertmanager instance
 	labels      map[string]string
 	annotations map[string]string
@@ -95,14 +341,20 @@ type Alertmanager struct {
 //   - TLS validation: Only TLS 1.2 and TLS 1.3 are accepted when TLSMinVersion/TLSMaxVersion are specified
 //
 // For more control over client configuration, use NewAlertmanager directly with ManagerOptions.
-// Returns nil if Enabled is false. Returns an error if configuration is invalid.
+// Returns nil if Enabled is false; use NewEmitterWithArgs to get a NopEmitter instead.
+// Returns an error listing all problems found by Args.Validate if configuration is invalid, except for
+// problems that were accepted before Validate was added, which are logged as warnings instead.
 func NewAlertmanagerWithArgs(logger logr.Logger, args Args) (*Alertmanager, error) {
 	if !args.Enabled {
 		return nil, nil
 	}
 
-	if args.AlertmanagerURL == "" {
-		return nil, fmt.Errorf("alertmanager URL must be provided when enabled")
+	errs, warnings := args.validate()
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid Alertmanager args: %w", err)
+	}
+	for _, warning := range warnings {
+		logger.Info("accepting invalid Alertmanager args; Args.Validate rejects them", "problem", warning.Error())
 	}
 
 	timeout := args.Timeout
@@ -119,10 +371,17 @@ func NewAlertmanagerWithArgs(logger logr.Logger, args Args) (*Alertmanager, erro
 		WithTimeout(timeout),
 	}
 
-	if args.Username != "" && args.Password != "" {
+	if args.PathPrefix != "" {
+		opts = append(opts, WithPathPrefix(args.PathPrefix))
+	}
+
+	switch {
+	case args.Password != "":
 		opts = append(opts, WithBasicAuth(args.Username, args.Password))
-	} else if args.Username != "" || args.Password != "" {
-		return nil, fmt.Errorf("both basic auth username and password must be provided together")
+	case args.PasswordFile != "":
+		opts = append(opts, WithBasicAuthFile(args.Username, args.PasswordFile))
+	case args.BearerTokenFile != "":
+		opts = append(opts, WithAuthorizationFile("Bearer", args.BearerTokenFile))
 	}
 
 	if args.TLSCACertPath != "" {
@@ -138,21 +397,31 @@ func NewAlertmanagerWithArgs(logger logr.Logger, args Args) (*Alertmanager, erro
 	}
 
 	if args.TLSMinVersion != "" {
-		minVersion, err := stringToSecureTLSVersion(args.TLSMinVersion)
-		if err != nil {
-			return nil, fmt.Errorf("invalid TLS min version: %w", err)
-		}
+		minVersion, _ := stringToSecureTLSVersion(args.TLSMinVersion)
 		opts = append(opts, WithMinTLSVersion(minVersion))
 	}
 
 	if args.TLSMaxVersion != "" {
-		maxVersion, err := stringToSecureTLSVersion(args.TLSMaxVersion)
-		if err != nil {
-			return nil, fmt.Errorf("invalid TLS max version: %w", err)
-		}
+		maxVersion, _ := stringToSecureTLSVersion(args.TLSMaxVersion)
 		opts = append(opts, WithMaxTLSVersion(maxVersion))
 	}
 
+	if args.ProxyURL != "" {
+		opts = append(opts, WithProxyURL(args.ProxyURL))
+	}
+
+	if args.NoProxy != "" {
+		opts = append(opts, WithNoProxy(args.NoProxy))
+	}
+
+	if args.ProxyFromEnvironment {
+		opts = append(opts, WithProxyFromEnvironment())
+	}
+
+	if args.DryRun {
+		opts = append(opts, WithDryRun(nil))
+	}
+
 	client, err := NewAlertmanager(logger, httpClient, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create alertmanager client: %w", err)
@@ -163,30 +432,59 @@ func NewAlertmanagerWithArgs(logger logr.Logger, args Args) (*Alertmanager, erro
 
 // NewAlertmanager creates a new Alertmanager instance with the provided logger, HTTP client, and options.
 // The logger and client are required. Use WithEndpoint() to set the endpoint.
+// Options apply to a copy of the client, so one client can be passed to several Alertmanagers.
 func NewAlertmanager(logger logr.Logger, client *http.Client, options ...ManagerOption) (*Alertmanager, error) {
 	if client == nil {
 		return nil, ErrNilHTTPClient
 	}
 
+	// options configure a shallow copy of the client, so the caller's client and transport
+	// are never modified and can be reused, e.g. by a ReloadFunc
+	clientCopy := *client
 	am := &Alertmanager{
-		client:      client,
+		client:      &clientCopy,
 		log:         logger,
 		labels:      make(map[string]string),
 		annotations: make(map[string]string),
 	}
 
+	// options log through a deferred logger, so that their messages reach the logger
+	// set by WithSlogLogger regardless of its position
+	deferred := &deferredLogSink{entries: new([]deferredLog)}
+	am.log = logr.New(deferred)
+	flush := func() {
+		if am.log.GetSink() == logr.LogSink(deferred) {
+			am.log = logger
+		}
+		deferred.replay(am.log)
+	}
+
 	// Apply all options
 	for _, opt := range options {
 		if err := opt(am); err != nil {
+			flush()
 			return nil, err
 		}
 	}
+	flush()
+	am.buildTransport()
 
 	return am, nil
 }
 
 // Emit sends one or more alerts to Alertmanager.
+// A nil Alertmanager discards the alerts, like NopEmitter.
 func (a *Alertmanager) Emit(alerts ...*Alert) (*http.Response, error) {
+	return a.EmitContext(context.Background(), alerts...)
+}
+
+// EmitContext sends one or more alerts to Alertmanager using the provided context.
+// If the context carries a tenant ID (see ContextWithTenant), it takes precedence over WithTenant.
+// A nil Alertmanager discards the alerts, like NopEmitter.
+func (a *Alertmanager) EmitContext(ctx context.Context, alerts ...*Alert) (*http.Response, error) {
+	if a == nil {
+		return nopResponse(), nil
+	}
 	if a.endpoint == "" {
 		return nil, ErrEndpointRequired
 	}
@@ -210,6 +508,23 @@ func (a *Alertmanager) Emit(alerts ...*Alert) (*http.Response, error) {
 		maps.Copy(mergedAlert.Annotations, a.annotations)
 		maps.Copy(mergedAlert.Annotations, alert.Annotations)
 
+		if a.traceLabels != nil {
+			a.traceLabels.apply(ctx, mergedAlert.Labels)
+		}
+		if a.traceAnnotations != nil {
+			a.traceAnnotations.apply(ctx, mergedAlert.Annotations)
+		}
+
+		if err := alert.renderTemplates(mergedAlert.Labels, mergedAlert.Annotations); err != nil {
+			return nil, fmt.Errorf("failed to render alert templates: %w", err)
+		}
+		// alerts are only validated in dry runs; otherwise Alertmanager decides whether to accept them
+		if a.dryRun != nil {
+			if err := mergedAlert.validate(); err != nil {
+				return nil, fmt.Errorf("alert %d: %w", len(finalAlerts), err)
+			}
+		}
+
 		finalAlerts = append(finalAlerts, mergedAlert)
 	}
 
@@ -218,24 +533,87 @@ func (a *Alertmanager) Emit(alerts ...*Alert) (*http.Response, error) {
 		return nil, fmt.Errorf("failed to marshal alerts: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, a.endpoint, bytes.NewReader(body))
+	endpoint := redactURL(a.endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request to %s: %w", a.endpoint, err)
+		return nil, fmt.Errorf("failed to create HTTP request to %s: %w", endpoint, err)
 	}
 	req.Header.Add("Content-Type", "application/json")
+	a.setHeaders(ctx, req)
 
-	if a.authHeader != "" {
-		req.Header.Add("Authorization", a.authHeader)
+	if a.dryRun != nil {
+		return a.emitDryRun(req, body, finalAlerts)
 	}
 
+	req, span := a.startSpan(req, "Emit", alertCountKey.Int(len(finalAlerts)))
+	start := time.Now()
 	resp, err := a.client.Do(req)
+	endSpan(span, resp, err)
+	if a.metrics != nil {
+		var statusCode int
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		a.metrics.observeRequest(a.displayURL, len(finalAlerts), statusCode, time.Since(start))
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to post alert to %s: %w", a.endpoint, err)
+		a.log.Error(err, "failed to send alerts", logKeyEndpoint, endpoint, logKeyAlertCount, len(finalAlerts),
+			logKeyPayload, a.logPayload(body, finalAlerts))
+		return nil, fmt.Errorf("failed to post alert to %s: %w", endpoint, err)
 	}
+	a.log.V(1).Info("sent alerts", logKeyEndpoint, endpoint, logKeyAlertCount, len(finalAlerts), logKeyStatus, resp.StatusCode,
+		logKeyPayload, a.logPayload(body, finalAlerts))
 
 	return resp, nil
 }
 
+// setHeaders adds the authorization and tenant headers to an API request.
+func (a *Alertmanager) setHeaders(ctx context.Context, req *http.Request) {
+	if a.authHeader != "" {
+		req.Header.Add("Authorization", a.authHeader)
+	}
+
+	if a.authFile != nil {
+		secret, err := a.authFile.get()
+		if err != nil {
+			a.log.Error(err, "failed to re-read credentials; using previous credentials")
+		}
+		req.Header.Add("Authorization", a.authFormat(secret))
+	}
+
+	if tenant := a.tenant(ctx); tenant != "" {
+		req.Header.Set(a.tenantHeaderName(), tenant)
+	}
+}
+
+// setEndpoint rebuilds the alerts endpoint from the base URL and path prefix.
+func (a *Alertmanager) setEndpoint() {
+	if a.baseURL == "" {
+		return
+	}
+	a.endpoint = a.apiURL("/api/v2/alerts")
+
+	a.displayURL = a.pathPrefix
+	if u, err := url.Parse(a.baseURL); err == nil {
+		u.User = nil
+		a.displayURL = u.String() + a.pathPrefix
+	}
+}
+
+// apiURL returns the URL of the given API path relative to the base URL and path prefix.
+func (a *Alertmanager) apiURL(path string) string {
+	return a.baseURL + a.pathPrefix + path
+}
+
+// clone returns a copy of the Alertmanager that shares the HTTP client and logger
+// but owns its base labels and annotations.
+func (a *Alertmanager) clone() *Alertmanager {
+	c := *a
+	c.labels = maps.Clone(a.labels)
+	c.annotations = maps.Clone(a.annotations)
+	return &c
+}
+
 func basicAuthHeader(username, password string) string {
 	auth := base64.StdEncoding.EncodeToString(
 		bytes.Join([][]byte{[]byte(username), []byte(password)}, []byte(":")),
@@ -1,12 +1,18 @@
 package alertmanager
 
 import (
+	"encoding/pem"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 )
 
 func TestNewAlertmanager(t *testing.T) {
@@ -305,3 +311,295 @@ func TestBasicAuthHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAlertmanagerWithArgs(t *testing.T) {
+	logger := logr.Discard()
+
+	tests := []struct {
+		name             string
+		args             Args
+		expectNil        bool
+		expectError      bool
+		expectedEndpoint string
+	}{
+		{
+			name:      "disabled",
+			args:      Args{Enabled: false, AlertmanagerURL: "http://alertmanager:9093"},
+			expectNil: true,
+		},
+		{
+			name:        "missing URL",
+			args:        Args{Enabled: true},
+			expectError: true,
+		},
+		{
+			name:             "path stripped by default",
+			args:             Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093/some/path"},
+			expectedEndpoint: "http://alertmanager:9093/api/v2/alerts",
+		},
+		{
+			name:             "with path prefix",
+			args:             Args{Enabled: true, AlertmanagerURL: "http://ingress:80", PathPrefix: "/alertmanager"},
+			expectedEndpoint: "http://ingress:80/alertmanager/api/v2/alerts",
+		},
+		{
+			name:        "username without password",
+			args:        Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", Username: "user"},
+			expectError: true,
+		},
+		{
+			name:        "password and password file",
+			args:        Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", Username: "user", Password: "pass", PasswordFile: "/etc/password"},
+			expectError: true,
+		},
+		{
+			name:        "password file without username",
+			args:        Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", PasswordFile: "/etc/password"},
+			expectError: true,
+		},
+		{
+			name:        "missing password file",
+			args:        Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", Username: "user", PasswordFile: "/nonexistent/password"},
+			expectError: true,
+		},
+		{
+			name:        "basic auth and bearer token file",
+			args:        Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", Username: "user", Password: "pass", BearerTokenFile: "/etc/token"},
+			expectError: true,
+		},
+		{
+			name:             "with proxy",
+			args:             Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", ProxyURL: "http://proxy:3128", NoProxy: "localhost"},
+			expectedEndpoint: "http://alertmanager:9093/api/v2/alerts",
+		},
+		{
+			name:        "no proxy without proxy URL",
+			args:        Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093", NoProxy: "localhost"},
+			expectError: true,
+		},
+		{
+			name:        "TLS min version greater than max version",
+			args:        Args{Enabled: true, AlertmanagerURL: "https://alertmanager:9093", TLSMinVersion: "TLS13", TLSMaxVersion: "TLS12"},
+			expectError: true,
+		},
+		{
+			name:        "invalid TLS min version",
+			args:        Args{Enabled: true, AlertmanagerURL: "https://alertmanager:9093", TLSMinVersion: "TLS10"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am, err := NewAlertmanagerWithArgs(logger, tt.args)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.expectNil {
+				if am != nil {
+					t.Errorf("expected nil Alertmanager")
+				}
+				return
+			}
+
+			if am.endpoint != tt.expectedEndpoint {
+				t.Errorf("expected endpoint to be '%s', got '%s'", tt.expectedEndpoint, am.endpoint)
+			}
+		})
+	}
+}
+
+func TestArgsValidate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	passwordPath := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordPath, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		args           Args
+		expectedErrors []string
+	}{
+		{
+			name: "valid",
+			args: Args{
+				Enabled:         true,
+				AlertmanagerURL: "https://alertmanager:9093",
+				Username:        "admin",
+				PasswordFile:    passwordPath,
+				TLSCACertPath:   caPath,
+				TLSMinVersion:   "TLS12",
+				TLSMaxVersion:   "TLS13",
+				ProxyURL:        "http://proxy:3128",
+				NoProxy:         "localhost",
+			},
+		},
+		{
+			name: "disabled without URL",
+			args: Args{Timeout: time.Second},
+		},
+		{
+			name: "all problems",
+			args: Args{
+				Enabled:               true,
+				AlertmanagerURL:       "alertmanager:9093",
+				Timeout:               -time.Second,
+				Password:              "secret",
+				PasswordFile:          passwordPath,
+				BearerTokenFile:       filepath.Join(dir, "missing"),
+				TLSCACertPath:         passwordPath,
+				TLSInsecureSkipVerify: true,
+				TLSMinVersion:         "TLS10",
+				TLSMaxVersion:         "SSL3",
+				NoProxy:               "localhost",
+			},
+			expectedErrors: []string{
+				"AlertmanagerURL: must be an http or https URL with a host",
+				"Timeout: must not be negative",
+				"Password: mutually exclusive with PasswordFile",
+				"BearerTokenFile: open",
+				"TLSCACertPath: mutually exclusive with TLSInsecureSkipVerify",
+				"TLSCACertPath: no PEM certificates found",
+				"TLSMinVersion: TLS 1.0 is not allowed",
+				"TLSMaxVersion: must be one of",
+				"NoProxy: requires ProxyURL",
+			},
+		},
+		{
+			name:           "missing URL",
+			args:           Args{Enabled: true},
+			expectedErrors: []string{"AlertmanagerURL: required when Enabled is set"},
+		},
+		{
+			name:           "username without password",
+			args:           Args{Username: "admin"},
+			expectedErrors: []string{"Username: requires Password or PasswordFile"},
+		},
+		{
+			name:           "password file without username",
+			args:           Args{PasswordFile: passwordPath},
+			expectedErrors: []string{"PasswordFile: requires Username"},
+		},
+		{
+			name:           "username without password and bearer token file",
+			args:           Args{Username: "admin", BearerTokenFile: passwordPath},
+			expectedErrors: []string{"BearerTokenFile: mutually exclusive with basic auth"},
+		},
+		{
+			name:           "basic auth and bearer token file",
+			args:           Args{Username: "admin", Password: "secret", BearerTokenFile: passwordPath},
+			expectedErrors: []string{"BearerTokenFile: mutually exclusive with basic auth"},
+		},
+		{
+			name:           "password file is a directory",
+			args:           Args{Username: "admin", PasswordFile: dir},
+			expectedErrors: []string{"PasswordFile: " + dir + " is a directory"},
+		},
+		{
+			name:           "inconsistent TLS range",
+			args:           Args{TLSMinVersion: "TLS13", TLSMaxVersion: "TLS12"},
+			expectedErrors: []string{"TLSMinVersion: TLS13 is greater than TLSMaxVersion TLS12"},
+		},
+		{
+			name:           "proxy URL and proxy from environment",
+			args:           Args{ProxyURL: "http://proxy:3128", ProxyFromEnvironment: true},
+			expectedErrors: []string{"ProxyURL: invalid Alertmanager config: proxy URL and proxy from environment are mutually exclusive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if len(tt.expectedErrors) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var joined interface{ Unwrap() []error }
+			if !errors.As(err, &joined) || len(joined.Unwrap()) != len(tt.expectedErrors) {
+				t.Errorf("expected %d errors, got %v", len(tt.expectedErrors), err)
+			}
+			for _, expected := range tt.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewAlertmanagerWithArgsWarnings(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		args             Args
+		expectedWarnings []string
+	}{
+		{
+			name:             "non-http scheme",
+			args:             Args{Enabled: true, AlertmanagerURL: "tcp://alertmanager:9093"},
+			expectedWarnings: []string{"AlertmanagerURL: must be an http or https URL with a host"},
+		},
+		{
+			name: "CA without PEM certificates and insecure",
+			args: Args{Enabled: true, AlertmanagerURL: "https://alertmanager:9093", TLSCACertPath: notPEM, TLSInsecureSkipVerify: true},
+			expectedWarnings: []string{
+				"TLSCACertPath: mutually exclusive with TLSInsecureSkipVerify",
+				"TLSCACertPath: no PEM certificates found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs []string
+			logger := funcr.New(func(prefix, args string) {
+				logs = append(logs, args)
+			}, funcr.Options{})
+
+			am, err := NewAlertmanagerWithArgs(logger, tt.args)
+			if err != nil {
+				t.Fatalf("expected args to be accepted, got %v", err)
+			}
+			if am == nil {
+				t.Fatal("expected Alertmanager, got nil")
+			}
+			if err := tt.args.Validate(); err == nil {
+				t.Errorf("expected Validate to reject args")
+			}
+
+			out := strings.Join(logs, "\n")
+			for _, expected := range tt.expectedWarnings {
+				if !strings.Contains(out, expected) {
+					t.Errorf("expected warning %q to be logged, got %s", expected, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package alertmanagertest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+// Matcher reports whether an alert matches a condition.
+type Matcher func(alert alertmanager.Alert) bool
+
+// HasLabel matches alerts with the given label value.
+func HasLabel(name, value string) Matcher {
+	return func(alert alertmanager.Alert) bool {
+		return alert.Labels[name] == value
+	}
+}
+
+// HasAnnotation matches alerts with the given annotation value.
+func HasAnnotation(name, value string) Matcher {
+	return func(alert alertmanager.Alert) bool {
+		return alert.Annotations[name] == value
+	}
+}
+
+// AnnotationContains matches alerts whose annotation contains substr.
+func AnnotationContains(name, substr string) Matcher {
+	return func(alert alertmanager.Alert) bool {
+		return strings.Contains(alert.Annotations[name], substr)
+	}
+}
+
+// Firing matches alerts without an end time or with an end time in the future.
+func Firing() Matcher {
+	return func(alert alertmanager.Alert) bool {
+		return alert.EndsAt == nil || alert.EndsAt.After(time.Now())
+	}
+}
+
+// Resolved matches alerts with an end time that is not in the future.
+func Resolved() Matcher {
+	return func(alert alertmanager.Alert) bool {
+		return alert.EndsAt != nil && !alert.EndsAt.After(time.Now())
+	}
+}
+
+// ParseMatcher parses a label matcher in Alertmanager syntax, e.g. `severity="critical"`,
+// `instance=~"web-.*"` or `env!=dev`. Regular expressions are anchored, as in Alertmanager.
+func ParseMatcher(s string) (Matcher, error) {
+	m, err := alertmanager.ParseSilenceMatcher(s)
+	if err != nil {
+		return nil, err
+	}
+	return silenceMatcher(m)
+}
+
+// MustParseMatcher is like ParseMatcher but panics if the matcher cannot be parsed.
+func MustParseMatcher(s string) Matcher {
+	m, err := ParseMatcher(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func newMatcher(name, op, value string) (Matcher, error) {
+	switch op {
+	case "=":
+		return func(alert alertmanager.Alert) bool { return alert.Labels[name] == value }, nil
+	case "!=":
+		return func(alert alertmanager.Alert) bool { return alert.Labels[name] != value }, nil
+	case "=~", "!~":
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher regex %q: %w", value, err)
+		}
+		negate := op == "!~"
+		return func(alert alertmanager.Alert) bool { return re.MatchString(alert.Labels[name]) != negate }, nil
+	default:
+		return nil, fmt.Errorf("invalid matcher operator %q", op)
+	}
+}
+
+func matchesAll(alert alertmanager.Alert, matchers []Matcher) bool {
+	for _, m := range matchers {
+		if !m(alert) {
+			return false
+		}
+	}
+	return true
+}
+
+// alertSource is a fake that records alerts, i.e. a Server or Recorder.
+type alertSource interface {
+	Alerts() []alertmanager.Alert
+}
+
+func findAlerts(src alertSource, matchers []Matcher) []alertmanager.Alert {
+	var found []alertmanager.Alert
+	for _, alert := range src.Alerts() {
+		if matchesAll(alert, matchers) {
+			found = append(found, alert)
+		}
+	}
+	return found
+}
+
+func requireAlert(t testing.TB, src alertSource, matchers []Matcher) alertmanager.Alert {
+	t.Helper()
+
+	found := findAlerts(src, matchers)
+	if len(found) == 0 {
+		all := src.Alerts()
+		t.Fatalf("expected a matching alert, got none of %d received alerts:\n%s", len(all), formatAlerts(all))
+	}
+	return found[len(found)-1]
+}
+
+func requireNoAlert(t testing.TB, src alertSource, matchers []Matcher) {
+	t.Helper()
+
+	if found := findAlerts(src, matchers); len(found) > 0 {
+		t.Fatalf("expected no matching alert, got %d:\n%s", len(found), formatAlerts(found))
+	}
+}
+
+func waitForAlert(t testing.TB, src alertSource, timeout time.Duration, matchers []Matcher) alertmanager.Alert {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if found := findAlerts(src, matchers); len(found) > 0 {
+			return found[len(found)-1]
+		}
+		if time.Now().After(deadline) {
+			all := src.Alerts()
+			t.Fatalf("expected a matching alert within %s, got none of %d received alerts:\n%s", timeout, len(all), formatAlerts(all))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// FindAlerts returns all received alerts that match all matchers.
+func (s *Server) FindAlerts(matchers ...Matcher) []alertmanager.Alert {
+	return findAlerts(s, matchers)
+}
+
+// RequireAlert fails the test unless an alert matching all matchers was received,
+// and returns the most recent one.
+func (s *Server) RequireAlert(t testing.TB, matchers ...Matcher) alertmanager.Alert {
+	t.Helper()
+	return requireAlert(t, s, matchers)
+}
+
+// RequireNoAlert fails the test if an alert matching all matchers was received.
+func (s *Server) RequireNoAlert(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	requireNoAlert(t, s, matchers)
+}
+
+// WaitForAlert waits until an alert matching all matchers was received, e.g. from an asynchronous
+// sender, and returns it. The test fails if no such alert is received within timeout.
+func (s *Server) WaitForAlert(t testing.TB, timeout time.Duration, matchers ...Matcher) alertmanager.Alert {
+	t.Helper()
+	return waitForAlert(t, s, timeout, matchers)
+}
+
+func formatAlerts(alerts []alertmanager.Alert) string {
+	var b strings.Builder
+	for _, alert := range alerts {
+		fmt.Fprintf(&b, "  labels=%v annotations=%v\n", alert.Labels, alert.Annotations)
+	}
+	return b.String()
+}
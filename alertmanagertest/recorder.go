@@ -0,0 +1,138 @@
+package alertmanagertest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+var _ alertmanager.Emitter = (*Recorder)(nil)
+
+// Recorder is an alertmanager.Emitter that records alerts in memory instead of sending them.
+// Unlike Server, it records alerts as emitted: base labels and annotations are not merged,
+// but label and annotation templates are rendered as by Emit.
+type Recorder struct {
+	mu     sync.Mutex
+	alerts []alertmanager.Alert
+	err    error
+}
+
+// NewRecorder creates a Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Emit records the alerts.
+func (r *Recorder) Emit(alerts ...*alertmanager.Alert) (*http.Response, error) {
+	return r.EmitContext(context.Background(), alerts...)
+}
+
+// EmitContext records the alerts, unless ctx is done, an error was set with SetError
+// or a template of an alert cannot be rendered. Either all alerts are recorded or none.
+func (r *Recorder) EmitContext(ctx context.Context, alerts ...*alertmanager.Alert) (*http.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	recorded := make([]alertmanager.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert == nil {
+			continue
+		}
+		c, err := copyAlert(alert)
+		if err != nil {
+			return nil, err
+		}
+		recorded = append(recorded, c)
+	}
+	r.alerts = append(r.alerts, recorded...)
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+	}, nil
+}
+
+// SetError makes subsequent calls to Emit and EmitContext fail with err. A nil err clears it.
+func (r *Recorder) SetError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// Alerts returns all recorded alerts, in the order they were emitted.
+func (r *Recorder) Alerts() []alertmanager.Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.alerts)
+}
+
+// Reset removes all recorded alerts and clears the error set with SetError.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = nil
+	r.err = nil
+}
+
+// FindAlerts returns all recorded alerts that match all matchers.
+func (r *Recorder) FindAlerts(matchers ...Matcher) []alertmanager.Alert {
+	return findAlerts(r, matchers)
+}
+
+// RequireAlert fails the test unless an alert matching all matchers was recorded,
+// and returns the most recent one.
+func (r *Recorder) RequireAlert(t testing.TB, matchers ...Matcher) alertmanager.Alert {
+	t.Helper()
+	return requireAlert(t, r, matchers)
+}
+
+// RequireNoAlert fails the test if an alert matching all matchers was recorded.
+func (r *Recorder) RequireNoAlert(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	requireNoAlert(t, r, matchers)
+}
+
+// WaitForAlert waits until an alert matching all matchers was recorded, e.g. from an asynchronous
+// sender, and returns it. The test fails if no such alert is recorded within timeout.
+func (r *Recorder) WaitForAlert(t testing.TB, timeout time.Duration, matchers ...Matcher) alertmanager.Alert {
+	t.Helper()
+	return waitForAlert(t, r, timeout, matchers)
+}
+
+// copyAlert returns a deep copy of the alert with its templates rendered.
+func copyAlert(alert *alertmanager.Alert) (alertmanager.Alert, error) {
+	rendered, err := alert.RenderTemplates()
+	if err != nil {
+		return alertmanager.Alert{}, fmt.Errorf("failed to render alert templates: %w", err)
+	}
+
+	c := alertmanager.Alert{
+		Labels:      rendered.Labels,
+		Annotations: rendered.Annotations,
+	}
+	if alert.StartsAt != nil {
+		startsAt := *alert.StartsAt
+		c.StartsAt = &startsAt
+	}
+	if alert.EndsAt != nil {
+		endsAt := *alert.EndsAt
+		c.EndsAt = &endsAt
+	}
+	return c, nil
+}
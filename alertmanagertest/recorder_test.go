@@ -0,0 +1,92 @@
+package alertmanagertest_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+	"github.com/spectrocloud-labs/alertmanager-client-go/alertmanagertest"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := alertmanagertest.NewRecorder()
+
+	var emitter alertmanager.Emitter = recorder
+	alert := alertmanager.NewAlert(alertmanager.WithLabel("alertname", "Test"), alertmanager.WithAnnotation("summary", "test"))
+	resp, err := emitter.Emit(alert)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	alert.Labels["alertname"] = "Modified"
+	recorder.RequireAlert(t, alertmanagertest.HasLabel("alertname", "Test"), alertmanagertest.HasAnnotation("summary", "test"))
+	recorder.RequireNoAlert(t, alertmanagertest.HasLabel("alertname", "Modified"))
+
+	errUnavailable := errors.New("unavailable")
+	recorder.SetError(errUnavailable)
+	if _, err := emitter.Emit(alert); !errors.Is(err, errUnavailable) {
+		t.Errorf("expected error %v, got %v", errUnavailable, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.SetError(nil)
+	if _, err := emitter.EmitContext(ctx, alert); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+	if len(recorder.Alerts()) != 1 {
+		t.Errorf("expected 1 alert, got %d", len(recorder.Alerts()))
+	}
+
+	recorder.Reset()
+	if len(recorder.Alerts()) != 0 {
+		t.Errorf("expected no alerts after reset, got %d", len(recorder.Alerts()))
+	}
+}
+
+func TestRecorderTemplates(t *testing.T) {
+	recorder := alertmanagertest.NewRecorder()
+
+	alert := alertmanager.NewAlert(
+		alertmanager.WithLabel("alertname", "DiskFull"),
+		alertmanager.WithLabel("instance", "db-1"),
+		alertmanager.WithLabelTemplate("host", "{{ $labels.instance | toUpper }}"),
+		alertmanager.WithAnnotationTemplate("summary", "{{ $labels.host }} is {{ $value }} full"),
+		alertmanager.WithValue(0.95),
+	)
+	if _, err := recorder.Emit(alert); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	recorder.RequireAlert(t,
+		alertmanagertest.HasLabel("host", "DB-1"),
+		alertmanagertest.HasAnnotation("summary", "DB-1 is 0.95 full"),
+	)
+
+	invalid := alertmanager.NewAlert(alertmanager.WithAnnotationTemplate("summary", "{{ $labels.instance"))
+	if _, err := recorder.Emit(alertmanager.NewAlert(), invalid); !errors.Is(err, alertmanager.ErrInvalidTemplate) {
+		t.Errorf("expected error %v, got %v", alertmanager.ErrInvalidTemplate, err)
+	}
+	if len(recorder.Alerts()) != 1 {
+		t.Errorf("expected no alerts to be recorded from a failed emit, got %d", len(recorder.Alerts())-1)
+	}
+}
+
+func TestRecorderWithAlertHandler(t *testing.T) {
+	recorder := alertmanagertest.NewRecorder()
+	handler := alertmanager.NewAlertHandler(slog.DiscardHandler, recorder)
+	logger := slog.New(handler)
+
+	logger.Error("database unreachable", "db", "orders", alertmanager.DefaultAlertMarkerKey, true)
+
+	recorder.WaitForAlert(t, time.Second,
+		alertmanagertest.HasLabel("alertname", alertmanager.DefaultLogAlertName),
+		alertmanagertest.HasAnnotation(alertmanager.SummaryAnnotation, "database unreachable"),
+	)
+	if err := handler.Close(context.Background()); err != nil {
+		t.Errorf("failed to close handler: %v", err)
+	}
+}
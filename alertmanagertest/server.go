@@ -0,0 +1,368 @@
+// Package alertmanagertest provides an in-memory fake Alertmanager server for testing code that
+// sends alerts. The server implements the v2 alerts, silences and status endpoints, records every
+// received alert, and supports basic auth, TLS and injected faults.
+package alertmanagertest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+// Request is a request received by the Server.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+}
+
+// Fault is a fault injected into the responses of the Server.
+type Fault struct {
+	// Latency delays every response.
+	Latency time.Duration
+
+	// StatusCode, if set, is returned instead of handling the request.
+	StatusCode int
+
+	// DropConnection closes the connection without responding.
+	DropConnection bool
+
+	// Times is the number of requests the fault applies to. Zero applies it until ClearFaults is called.
+	Times int
+}
+
+// Server is a fake Alertmanager server. Create it with NewServer.
+type Server struct {
+	*httptest.Server
+
+	username   string
+	password   string
+	tls        bool
+	caCertPath string
+	started    time.Time
+
+	mu       sync.Mutex
+	received []alertmanager.Alert
+	alerts   map[string]*storedAlert
+	silences map[string]*Silence
+	requests []Request
+	faults   []Fault
+}
+
+type storedAlert struct {
+	alert     alertmanager.Alert
+	updatedAt time.Time
+}
+
+// Option represents a configuration option for a Server.
+type Option func(*Server)
+
+// WithBasicAuth requires requests to use basic authentication with the given credentials.
+func WithBasicAuth(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithTLS serves HTTPS using a self-signed certificate. Use the Client method or CACertPath
+// to trust it.
+func WithTLS() Option {
+	return func(s *Server) {
+		s.tls = true
+	}
+}
+
+// NewServer starts a fake Alertmanager server that is closed when the test completes.
+func NewServer(t testing.TB, options ...Option) *Server {
+	t.Helper()
+
+	s := &Server{
+		started:  time.Now(),
+		alerts:   make(map[string]*storedAlert),
+		silences: make(map[string]*Silence),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/alerts", s.postAlerts)
+	mux.HandleFunc("GET /api/v2/alerts", s.getAlerts)
+	mux.HandleFunc("POST /api/v2/silences", s.postSilence)
+	mux.HandleFunc("GET /api/v2/silences", s.getSilences)
+	mux.HandleFunc("GET /api/v2/silence/{id}", s.getSilence)
+	mux.HandleFunc("DELETE /api/v2/silence/{id}", s.deleteSilence)
+	mux.HandleFunc("GET /api/v2/status", s.getStatus)
+
+	s.Server = httptest.NewUnstartedServer(s.middleware(mux))
+	if s.tls {
+		s.StartTLS()
+		s.caCertPath = filepath.Join(t.TempDir(), "ca.crt")
+		cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+		if err := os.WriteFile(s.caCertPath, cert, 0o600); err != nil {
+			t.Fatalf("failed to write CA certificate: %v", err)
+		}
+	} else {
+		s.Start()
+	}
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// CACertPath returns the path of a PEM file containing the certificate of a server created
+// WithTLS, e.g. for the TLSCACertPath field of alertmanager.Args. It is empty otherwise.
+func (s *Server) CACertPath() string {
+	return s.caCertPath
+}
+
+// Alerts returns all alerts received by the server, in the order they were received.
+func (s *Server) Alerts() []alertmanager.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.received)
+}
+
+// Requests returns all requests received by the server, including rejected ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Silences returns all silences created on the server.
+func (s *Server) Silences() []Silence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listSilences(time.Now())
+}
+
+// InjectFault adds a fault to subsequent requests. Faults are applied in the order they were
+// injected; a fault with Times set is removed once it was applied that many times.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Reset removes all received alerts, requests, silences and faults.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = nil
+	s.alerts = make(map[string]*storedAlert)
+	s.silences = make(map[string]*Silence)
+	s.requests = nil
+	s.faults = nil
+}
+
+// middleware records requests, applies faults and checks basic auth.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone()})
+		fault, ok := s.nextFault()
+		s.mu.Unlock()
+
+		if ok {
+			if fault.Latency > 0 {
+				select {
+				case <-time.After(fault.Latency):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if fault.DropConnection {
+				dropConnection(w)
+				return
+			}
+			if fault.StatusCode != 0 {
+				http.Error(w, http.StatusText(fault.StatusCode), fault.StatusCode)
+				return
+			}
+		}
+
+		if s.username != "" || s.password != "" {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != s.username || password != s.password {
+				w.Header().Set("WWW-Authenticate", `Basic realm="alertmanager"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// nextFault returns the fault to apply to a request. Must be called with s.mu held.
+func (s *Server) nextFault() (Fault, bool) {
+	if len(s.faults) == 0 {
+		return Fault{}, false
+	}
+
+	f := s.faults[0]
+	if f.Times > 0 {
+		s.faults[0].Times--
+		if s.faults[0].Times == 0 {
+			s.faults = s.faults[1:]
+		}
+	}
+	return f, true
+}
+
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	conn.Close()
+}
+
+func (s *Server) postAlerts(w http.ResponseWriter, r *http.Request) {
+	var alerts []alertmanager.Alert
+	if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+		http.Error(w, fmt.Sprintf("invalid alerts: %v", err), http.StatusBadRequest)
+		return
+	}
+	for i, alert := range alerts {
+		if len(alert.Labels) == 0 {
+			http.Error(w, fmt.Sprintf("invalid alert %d: at least one label pair required", i), http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, alert := range alerts {
+		s.received = append(s.received, alert)
+
+		stored := alertmanager.Alert{
+			Labels:      maps.Clone(alert.Labels),
+			Annotations: maps.Clone(alert.Annotations),
+			StartsAt:    alert.StartsAt,
+			EndsAt:      alert.EndsAt,
+		}
+		if stored.StartsAt == nil {
+			stored.StartsAt = &now
+		}
+		if existing, ok := s.alerts[fingerprint(alert.Labels)]; ok && existing.alert.StartsAt.Before(*stored.StartsAt) {
+			stored.StartsAt = existing.alert.StartsAt
+		}
+		s.alerts[fingerprint(alert.Labels)] = &storedAlert{alert: stored, updatedAt: now}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getAlerts(w http.ResponseWriter, r *http.Request) {
+	var filter []Matcher
+	for _, f := range r.URL.Query()["filter"] {
+		m, err := ParseMatcher(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter = append(filter, m)
+	}
+	includeActive := r.URL.Query().Get("active") != "false"
+	includeSilenced := r.URL.Query().Get("silenced") != "false"
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alerts := make([]alertmanager.GettableAlert, 0, len(s.alerts))
+	for _, fp := range slices.Sorted(maps.Keys(s.alerts)) {
+		stored := s.alerts[fp]
+		if stored.alert.EndsAt != nil && stored.alert.EndsAt.Before(now) {
+			continue
+		}
+		if !matchesAll(stored.alert, filter) {
+			continue
+		}
+
+		silencedBy := s.silencedBy(stored.alert, now)
+		state := "active"
+		if len(silencedBy) > 0 {
+			state = "suppressed"
+		}
+		if (state == "active" && !includeActive) || (state == "suppressed" && !includeSilenced) {
+			continue
+		}
+
+		alerts = append(alerts, alertmanager.GettableAlert{
+			Alert:       stored.alert,
+			UpdatedAt:   stored.updatedAt,
+			Fingerprint: fp,
+			Receivers:   []alertmanager.Receiver{{Name: "default"}},
+			Status:      alertmanager.AlertStatus{State: state, SilencedBy: silencedBy, InhibitedBy: []string{}},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+func (s *Server) getStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"cluster": map[string]any{
+			"status": "ready",
+			"peers":  []any{},
+		},
+		"config": map[string]string{
+			"original": "",
+		},
+		"uptime": s.started.UTC(),
+		"versionInfo": map[string]string{
+			"version": "alertmanagertest",
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fingerprint returns a string that uniquely identifies a label set.
+func fingerprint(labels map[string]string) string {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		b.WriteString(k)
+		b.WriteByte(0xff)
+		b.WriteString(labels[k])
+		b.WriteByte(0xff)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(b.String()))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func newID() string {
+	return rand.Text()
+}
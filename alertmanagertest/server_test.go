@@ -0,0 +1,275 @@
+package alertmanagertest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+	"github.com/spectrocloud-labs/alertmanager-client-go/alertmanagertest"
+)
+
+func newClient(t *testing.T, server *alertmanagertest.Server, options ...alertmanager.ManagerOption) *alertmanager.Alertmanager {
+	t.Helper()
+	am, err := alertmanager.NewAlertmanager(logr.Discard(), &http.Client{Timeout: time.Second},
+		append([]alertmanager.ManagerOption{alertmanager.WithEndpoint(server.URL)}, options...)...)
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+	return am
+}
+
+func emit(t *testing.T, am *alertmanager.Alertmanager, alerts ...*alertmanager.Alert) int {
+	t.Helper()
+	resp, err := am.Emit(alerts...)
+	if err != nil {
+		t.Fatalf("failed to emit alerts: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func getJSON(t *testing.T, rawURL string, v any) {
+	t.Helper()
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Fatalf("failed to get %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+}
+
+func TestServerAlerts(t *testing.T) {
+	server := alertmanagertest.NewServer(t)
+	am := newClient(t, server)
+
+	emit(t, am,
+		alertmanager.NewAlert(alertmanager.WithLabel("alertname", "Down"), alertmanager.WithLabel("instance", "web-1"),
+			alertmanager.WithAnnotation("summary", "web-1 is down")),
+		alertmanager.NewAlert(alertmanager.WithLabel("alertname", "Down"), alertmanager.WithLabel("instance", "db-1")),
+		alertmanager.NewAlert(alertmanager.WithLabel("alertname", "Old"), alertmanager.WithEndsAt(time.Now().Add(-time.Minute))),
+	)
+
+	alert := server.RequireAlert(t, alertmanagertest.HasLabel("instance", "web-1"), alertmanagertest.Firing())
+	if alert.Annotations["summary"] != "web-1 is down" {
+		t.Errorf("expected summary annotation, got %v", alert.Annotations)
+	}
+	server.RequireAlert(t, alertmanagertest.HasLabel("alertname", "Old"), alertmanagertest.Resolved())
+	server.RequireNoAlert(t, alertmanagertest.HasLabel("instance", "web-2"))
+
+	var active []struct {
+		Labels map[string]string `json:"labels"`
+		Status struct {
+			State      string   `json:"state"`
+			SilencedBy []string `json:"silencedBy"`
+		} `json:"status"`
+	}
+	getJSON(t, server.URL+"/api/v2/alerts", &active)
+	if len(active) != 2 {
+		t.Fatalf("expected 2 active alerts, got %d", len(active))
+	}
+
+	body := `{"matchers":[{"name":"instance","value":"db-.*","isRegex":true}],` +
+		`"startsAt":"` + time.Now().Add(-time.Minute).Format(time.RFC3339) + `",` +
+		`"endsAt":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `","createdBy":"test","comment":"maintenance"}`
+	resp, err := http.Post(server.URL+"/api/v2/silences", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create silence: %v", err)
+	}
+	var created struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode silence: %v", err)
+	}
+	resp.Body.Close()
+
+	getJSON(t, server.URL+"/api/v2/alerts?filter="+url.QueryEscape(`instance=~"db-.*"`), &active)
+	if len(active) != 1 || active[0].Status.State != "suppressed" || active[0].Status.SilencedBy[0] != created.SilenceID {
+		t.Errorf("expected db-1 to be silenced by %s, got %+v", created.SilenceID, active)
+	}
+	getJSON(t, server.URL+"/api/v2/alerts?silenced=false", &active)
+	if len(active) != 1 || active[0].Labels["instance"] != "web-1" {
+		t.Errorf("expected only web-1 to be returned, got %+v", active)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/v2/silence/"+created.SilenceID, nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to expire silence: %v", err)
+	}
+	resp.Body.Close()
+
+	silences := server.Silences()
+	if len(silences) != 1 || silences[0].Status.State != "expired" {
+		t.Errorf("expected silence to be expired, got %+v", silences)
+	}
+
+	var status struct {
+		Cluster struct {
+			Status string `json:"status"`
+		} `json:"cluster"`
+	}
+	getJSON(t, server.URL+"/api/v2/status", &status)
+	if status.Cluster.Status != "ready" {
+		t.Errorf("expected cluster status ready, got %q", status.Cluster.Status)
+	}
+}
+
+func TestServerAuthAndTLS(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        []alertmanagertest.Option
+		args           func(server *alertmanagertest.Server) alertmanager.Args
+		expectedStatus int
+	}{
+		{
+			name:    "valid basic auth",
+			options: []alertmanagertest.Option{alertmanagertest.WithBasicAuth("user", "pass")},
+			args: func(server *alertmanagertest.Server) alertmanager.Args {
+				return alertmanager.Args{AlertmanagerURL: server.URL, Username: "user", Password: "pass"}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "invalid basic auth",
+			options: []alertmanagertest.Option{alertmanagertest.WithBasicAuth("user", "pass")},
+			args: func(server *alertmanagertest.Server) alertmanager.Args {
+				return alertmanager.Args{AlertmanagerURL: server.URL, Username: "user", Password: "wrong"}
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "TLS with CA certificate",
+			options: []alertmanagertest.Option{alertmanagertest.WithTLS()},
+			args: func(server *alertmanagertest.Server) alertmanager.Args {
+				return alertmanager.Args{AlertmanagerURL: server.URL, TLSCACertPath: server.CACertPath()}
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := alertmanagertest.NewServer(t, tt.options...)
+			args := tt.args(server)
+			args.Enabled = true
+
+			am, err := alertmanager.NewAlertmanagerWithArgs(logr.Discard(), args)
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			status := emit(t, am, alertmanager.NewAlert(alertmanager.WithLabel("alertname", "Test")))
+			if status != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, status)
+			}
+			if received := len(server.Alerts()); (status == http.StatusOK) != (received == 1) {
+				t.Errorf("expected alerts to be recorded only on success, got %d", received)
+			}
+		})
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		fault          alertmanagertest.Fault
+		expectErr      bool
+		expectedStatus int
+	}{
+		{
+			name:           "status code",
+			fault:          alertmanagertest.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:      "dropped connection",
+			fault:     alertmanagertest.Fault{DropConnection: true, Times: 1},
+			expectErr: true,
+		},
+		{
+			name:      "latency exceeding the client timeout",
+			fault:     alertmanagertest.Fault{Latency: 500 * time.Millisecond, Times: 1},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := alertmanagertest.NewServer(t)
+			am := newClient(t, server, alertmanager.WithTimeout(200*time.Millisecond))
+			server.InjectFault(tt.fault)
+
+			alert := alertmanager.NewAlert(alertmanager.WithLabel("alertname", "Test"))
+			resp, err := am.EmitContext(context.Background(), alert)
+			if tt.expectErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("expected error, got nil")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.expectedStatus {
+					t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+				}
+			}
+			server.RequireNoAlert(t)
+
+			// the fault only applies once
+			if status := emit(t, am, alert); status != http.StatusOK {
+				t.Errorf("expected status 200 after the fault, got %d", status)
+			}
+			server.RequireAlert(t, alertmanagertest.HasLabel("alertname", "Test"))
+			if len(server.Requests()) != 2 {
+				t.Errorf("expected 2 requests, got %d", len(server.Requests()))
+			}
+		})
+	}
+}
+
+func TestParseMatcher(t *testing.T) {
+	alert := alertmanager.Alert{Labels: map[string]string{"severity": "critical", "instance": "web-1"}}
+
+	tests := []struct {
+		matcher       string
+		expectedMatch bool
+		expectErr     bool
+	}{
+		{matcher: `severity="critical"`, expectedMatch: true},
+		{matcher: `severity=warning`, expectedMatch: false},
+		{matcher: `severity!="warning"`, expectedMatch: true},
+		{matcher: `instance=~"web-.*"`, expectedMatch: true},
+		{matcher: `instance=~"web"`, expectedMatch: false},
+		{matcher: `instance!~"db-.*"`, expectedMatch: true},
+		{matcher: `missing=""`, expectedMatch: true},
+		{matcher: `instance=~"("`, expectErr: true},
+		{matcher: `=value`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.matcher, func(t *testing.T) {
+			m, err := alertmanagertest.ParseMatcher(tt.matcher)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if err == nil && m(alert) != tt.expectedMatch {
+				t.Errorf("expected match %v, got %v", tt.expectedMatch, m(alert))
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package alertmanagertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+// Silence, SilenceMatcher and SilenceStatus are the silence types of the v2 API.
+type (
+	Silence        = alertmanager.Silence
+	SilenceMatcher = alertmanager.SilenceMatcher
+	SilenceStatus  = alertmanager.SilenceStatus
+)
+
+// silenceMatcher converts a silence matcher into a Matcher.
+func silenceMatcher(m SilenceMatcher) (Matcher, error) {
+	return newMatcher(m.Name, m.Operator(), m.Value)
+}
+
+func silenceState(s *Silence, now time.Time) string {
+	switch {
+	case now.Before(s.StartsAt):
+		return "pending"
+	case now.Before(s.EndsAt):
+		return "active"
+	default:
+		return "expired"
+	}
+}
+
+// silenceMatches returns whether a silence applies to an alert.
+func silenceMatches(s *Silence, alert alertmanager.Alert) bool {
+	for _, sm := range s.Matchers {
+		m, err := silenceMatcher(sm)
+		if err != nil || !m(alert) {
+			return false
+		}
+	}
+	return true
+}
+
+// silencedBy returns the IDs of the active silences that apply to an alert. Must be called with s.mu held.
+func (s *Server) silencedBy(alert alertmanager.Alert, now time.Time) []string {
+	ids := []string{}
+	for id, silence := range s.silences {
+		if silenceState(silence, now) == "active" && silenceMatches(silence, alert) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// listSilences returns copies of all silences with their current status. Must be called with s.mu held.
+func (s *Server) listSilences(now time.Time) []Silence {
+	silences := make([]Silence, 0, len(s.silences))
+	for _, silence := range s.silences {
+		silences = append(silences, s.silenceWithStatus(silence, now))
+	}
+	slices.SortFunc(silences, func(a, b Silence) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	return silences
+}
+
+func (s *Server) silenceWithStatus(silence *Silence, now time.Time) Silence {
+	c := *silence
+	c.Matchers = slices.Clone(silence.Matchers)
+	c.Status = &SilenceStatus{State: silenceState(silence, now)}
+	return c
+}
+
+func (s *Server) postSilence(w http.ResponseWriter, r *http.Request) {
+	var silence Silence
+	if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+		http.Error(w, fmt.Sprintf("invalid silence: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(silence.Matchers) == 0 {
+		http.Error(w, "invalid silence: at least one matcher required", http.StatusBadRequest)
+		return
+	}
+	for _, m := range silence.Matchers {
+		if _, err := silenceMatcher(m); err != nil {
+			http.Error(w, fmt.Sprintf("invalid silence: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		http.Error(w, "invalid silence: end time must be after start time", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if silence.ID != "" {
+		if _, ok := s.silences[silence.ID]; !ok {
+			http.Error(w, fmt.Sprintf("silence %s not found", silence.ID), http.StatusNotFound)
+			return
+		}
+	} else {
+		silence.ID = newID()
+	}
+	silence.UpdatedAt = now
+	silence.Status = nil
+	s.silences[silence.ID] = &silence
+
+	writeJSON(w, http.StatusOK, map[string]string{"silenceID": silence.ID})
+}
+
+func (s *Server) getSilences(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.listSilences(time.Now()))
+}
+
+func (s *Server) getSilence(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	silence, ok := s.silences[r.PathValue("id")]
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.silenceWithStatus(silence, time.Now()))
+}
+
+func (s *Server) deleteSilence(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	silence, ok := s.silences[r.PathValue("id")]
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	if silenceState(silence, now) != "expired" {
+		silence.EndsAt = now
+		if silence.StartsAt.After(now) {
+			silence.StartsAt = now
+		}
+		silence.UpdatedAt = now
+	}
+	w.WriteHeader(http.StatusOK)
+}
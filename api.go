@@ -0,0 +1,252 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBodySize is the maximum number of bytes of an error response included in errors.
+const maxErrorBodySize = 1 << 10
+
+// ErrUnexpectedStatus is returned when the Alertmanager API responds with a non-2xx status code.
+var ErrUnexpectedStatus = errors.New("unexpected Alertmanager API response")
+
+// GettableAlert is an alert as returned by the Alertmanager v2 API.
+type GettableAlert struct {
+	Alert
+
+	// Fingerprint uniquely identifies the label set of the alert.
+	Fingerprint string `json:"fingerprint"`
+
+	// UpdatedAt is the time the alert was last received.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Receivers are the receivers the alert is routed to.
+	Receivers []Receiver `json:"receivers"`
+
+	// Status is whether the alert is active or suppressed, and by which silences or inhibitions.
+	Status AlertStatus `json:"status"`
+
+	// GeneratorURL identifies the source of the alert.
+	GeneratorURL string `json:"generatorURL,omitempty"`
+}
+
+// Receiver is an Alertmanager receiver.
+type Receiver struct {
+	Name string `json:"name"`
+}
+
+// AlertStatus is the status of an alert.
+type AlertStatus struct {
+	// State is one of unprocessed, active or suppressed.
+	State string `json:"state"`
+
+	// SilencedBy are the IDs of the silences that suppress the alert.
+	SilencedBy []string `json:"silencedBy"`
+
+	// InhibitedBy are the fingerprints of the alerts that inhibit the alert.
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// Silence is an Alertmanager silence.
+type Silence struct {
+	// ID is set by Alertmanager. Set it when posting a silence to update an existing one.
+	ID string `json:"id,omitempty"`
+
+	// Matchers select the alerts the silence applies to.
+	Matchers []SilenceMatcher `json:"matchers"`
+
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+
+	// UpdatedAt and Status are set by Alertmanager.
+	UpdatedAt time.Time      `json:"updatedAt,omitzero"`
+	Status    *SilenceStatus `json:"status,omitempty"`
+}
+
+// SilenceMatcher matches a label of the alerts a silence applies to.
+type SilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+
+	// IsEqual is false for negative matchers. Nil is treated as true.
+	IsEqual *bool `json:"isEqual,omitempty"`
+}
+
+// SilenceStatus is the state of a silence: active, pending or expired.
+type SilenceStatus struct {
+	State string `json:"state"`
+}
+
+// String formats the matcher in Alertmanager syntax, e.g. `instance=~"web-.*"`.
+func (m SilenceMatcher) String() string {
+	return m.Name + m.Operator() + strconv.Quote(m.Value)
+}
+
+// Operator returns the matching operator: =, !=, =~ or !~.
+func (m SilenceMatcher) Operator() string {
+	equal := m.IsEqual == nil || *m.IsEqual
+	switch {
+	case m.IsRegex && equal:
+		return "=~"
+	case m.IsRegex:
+		return "!~"
+	case equal:
+		return "="
+	default:
+		return "!="
+	}
+}
+
+// ParseSilenceMatcher parses a label matcher in Alertmanager syntax, e.g. `severity="critical"`,
+// `instance=~"web-.*"` or `env!=dev`. Quoting the value is optional.
+func ParseSilenceMatcher(s string) (SilenceMatcher, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexAny(s, "=!")
+	if i <= 0 {
+		return SilenceMatcher{}, fmt.Errorf("invalid matcher %q", s)
+	}
+
+	m := SilenceMatcher{Name: strings.TrimSpace(s[:i])}
+	rest := s[i:]
+	var op string
+	for _, candidate := range []string{"=~", "!~", "!=", "="} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return SilenceMatcher{}, fmt.Errorf("invalid matcher %q", s)
+	}
+	m.IsRegex = strings.HasSuffix(op, "~")
+	if strings.HasPrefix(op, "!") {
+		m.IsEqual = new(bool)
+	}
+
+	m.Value = strings.TrimSpace(rest[len(op):])
+	if strings.HasPrefix(m.Value, `"`) {
+		value, err := strconv.Unquote(m.Value)
+		if err != nil {
+			return SilenceMatcher{}, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		m.Value = value
+	}
+	return m, nil
+}
+
+// ListAlerts returns the alerts known to Alertmanager that match all filter matchers,
+// e.g. `severity="critical"`. Resolved alerts are not returned.
+func (a *Alertmanager) ListAlerts(ctx context.Context, filter ...string) ([]GettableAlert, error) {
+	query := url.Values{}
+	for _, f := range filter {
+		query.Add("filter", f)
+	}
+
+	var alerts []GettableAlert
+	if err := a.do(ctx, "ListAlerts", http.MethodGet, "/api/v2/alerts", query, nil, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// ListSilences returns the silences known to Alertmanager that match all filter matchers.
+func (a *Alertmanager) ListSilences(ctx context.Context, filter ...string) ([]Silence, error) {
+	query := url.Values{}
+	for _, f := range filter {
+		query.Add("filter", f)
+	}
+
+	var silences []Silence
+	if err := a.do(ctx, "ListSilences", http.MethodGet, "/api/v2/silences", query, nil, &silences); err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+// CreateSilence creates a silence, or updates it if its ID is set, and returns its ID.
+// In dry-run mode, the silence is logged instead and an empty ID is returned.
+func (a *Alertmanager) CreateSilence(ctx context.Context, silence Silence) (string, error) {
+	var result struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := a.do(ctx, "CreateSilence", http.MethodPost, "/api/v2/silences", nil, silence, &result); err != nil {
+		return "", err
+	}
+	return result.SilenceID, nil
+}
+
+// ExpireSilence expires the silence with the given ID. In dry-run mode, the request is logged instead.
+func (a *Alertmanager) ExpireSilence(ctx context.Context, id string) error {
+	return a.do(ctx, "ExpireSilence", http.MethodDelete, "/api/v2/silence/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// do sends a request to the Alertmanager API, encoding in as the JSON request body if not nil
+// and decoding the JSON response body into out if not nil. In dry-run mode, requests that change
+// the state of Alertmanager are logged instead of sent.
+func (a *Alertmanager) do(ctx context.Context, operation, method, path string, query url.Values, in, out any) error {
+	if a.baseURL == "" {
+		return ErrEndpointRequired
+	}
+
+	var payload []byte
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		payload, body = b, bytes.NewReader(b)
+	}
+
+	u := a.apiURL(path)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	if a.dryRun != nil && method != http.MethodGet {
+		a.log.Info("dry run: not sending request", "operation", operation, logKeyEndpoint, redactURL(u),
+			logKeyPayload, string(payload))
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request to %s: %w", redactURL(u), err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	a.setHeaders(ctx, req)
+
+	req, span := a.startSpan(req, operation)
+	resp, err := a.client.Do(req)
+	endSpan(span, resp, err)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", redactURL(u), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("%w: %s %s: %s: %s", ErrUnexpectedStatus, method, redactURL(u), resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response from %s: %w", redactURL(u), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestParseSilenceMatcher(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{input: `severity="critical"`, expected: `severity="critical"`},
+		{input: `env=dev`, expected: `env="dev"`},
+		{input: ` env != dev `, expected: `env!="dev"`},
+		{input: `instance=~"web-.*"`, expected: `instance=~"web-.*"`},
+		{input: `instance!~db-.*`, expected: `instance!~"db-.*"`},
+		{input: `=value`, expectErr: true},
+		{input: `name`, expectErr: true},
+		{input: `name="unterminated`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := ParseSilenceMatcher(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if err == nil && m.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, m.String())
+			}
+		})
+	}
+}
+
+func TestAPI(t *testing.T) {
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /prefix/api/v2/alerts", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("filter"))
+		if r.Header.Get("Authorization") == "" {
+			t.Error("expected Authorization header")
+		}
+		_ = json.NewEncoder(w).Encode([]GettableAlert{{
+			Alert:       Alert{Labels: map[string]string{"alertname": "Test"}},
+			Fingerprint: "abc",
+			Status:      AlertStatus{State: "active"},
+		}})
+	})
+	mux.HandleFunc("POST /prefix/api/v2/silences", func(w http.ResponseWriter, r *http.Request) {
+		var silence Silence
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			t.Errorf("failed to decode silence: %v", err)
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+silence.Matchers[0].String())
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": "s1"})
+	})
+	mux.HandleFunc("GET /prefix/api/v2/silences", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" ")
+		_ = json.NewEncoder(w).Encode([]Silence{{ID: "s1", Status: &SilenceStatus{State: "active"}}})
+	})
+	mux.HandleFunc("DELETE /prefix/api/v2/silence/{id}", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" ")
+		if r.PathValue("id") == "missing" {
+			http.Error(w, "silence not found", http.StatusNotFound)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{},
+		WithEndpoint(server.URL), WithPathPrefix("/prefix"), WithBasicAuth("user", "pass"))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+	ctx := context.Background()
+
+	alerts, err := am.ListAlerts(ctx, `alertname="Test"`)
+	if err != nil {
+		t.Fatalf("failed to list alerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Labels["alertname"] != "Test" || alerts[0].Status.State != "active" {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+
+	id, err := am.CreateSilence(ctx, Silence{
+		Matchers: []SilenceMatcher{{Name: "alertname", Value: "Test"}},
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(time.Hour),
+	})
+	if err != nil || id != "s1" {
+		t.Fatalf("expected silence s1, got %q, %v", id, err)
+	}
+
+	silences, err := am.ListSilences(ctx)
+	if err != nil || len(silences) != 1 || silences[0].Status.State != "active" {
+		t.Fatalf("unexpected silences %+v, %v", silences, err)
+	}
+
+	if err := am.ExpireSilence(ctx, "s1"); err != nil {
+		t.Errorf("failed to expire silence: %v", err)
+	}
+	if err := am.ExpireSilence(ctx, "missing"); !errors.Is(err, ErrUnexpectedStatus) {
+		t.Errorf("expected error %v, got %v", ErrUnexpectedStatus, err)
+	}
+
+	expected := []string{
+		`GET /prefix/api/v2/alerts alertname="Test"`,
+		`POST /prefix/api/v2/silences alertname="Test"`,
+		`GET /prefix/api/v2/silences `,
+		`DELETE /prefix/api/v2/silence/s1 `,
+		`DELETE /prefix/api/v2/silence/missing `,
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("expected requests %v, got %v", expected, requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Errorf("expected request %q, got %q", expected[i], requests[i])
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultQueueSize is the default number of alerts buffered by asynchronous senders.
+	DefaultQueueSize = 100
+
+	// DefaultSendTimeout is the default timeout for delivering a batch of alerts asynchronously.
+	DefaultSendTimeout = 5 * time.Second
+
+	// maxBatchSize is the maximum number of queued alerts sent in a single request.
+	maxBatchSize = 64
+)
+
+// ErrQueueClosed is returned when alerts are queued after an asynchronous sender was closed.
+var ErrQueueClosed = errors.New("alert queue closed")
+
+// asyncConfig configures an asyncEmitter.
+type asyncConfig struct {
+	queueName   string
+	queueSize   int
+	sendTimeout time.Duration
+	limit       rate.Limit
+	burst       int
+}
+
+// defaultAsyncConfig returns the default configuration: a queue with the given name of DefaultQueueSize
+// alerts delivered with DefaultSendTimeout, rate limited to one alert per second with a burst of 10.
+func defaultAsyncConfig(queueName string) asyncConfig {
+	return asyncConfig{
+		queueName:   queueName,
+		queueSize:   DefaultQueueSize,
+		sendTimeout: DefaultSendTimeout,
+		limit:       rate.Limit(1),
+		burst:       10,
+	}
+}
+
+// asyncEmitter delivers alerts to Alertmanager in the background so that callers never block on HTTP.
+// Alerts are dropped when the rate limit is exceeded or the queue is full.
+type asyncEmitter struct {
+	am      Emitter
+	log     logr.Logger
+	cfg     asyncConfig
+	limiter *rate.Limiter
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *Alert
+	done   chan struct{}
+}
+
+func newAsyncEmitter(am Emitter, cfg asyncConfig) *asyncEmitter {
+	e := &asyncEmitter{
+		am:      am,
+		log:     emitterLogger(am),
+		cfg:     cfg,
+		limiter: rate.NewLimiter(cfg.limit, cfg.burst),
+		queue:   make(chan *Alert, cfg.queueSize),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// enqueue queues an alert for delivery without blocking.
+// Returns false if the alert was dropped.
+func (e *asyncEmitter) enqueue(alert *Alert) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed || !e.limiter.Allow() {
+		e.drop(1)
+		return false
+	}
+
+	select {
+	case e.queue <- alert:
+		e.observeQueue()
+		return true
+	default:
+		e.drop(1)
+		return false
+	}
+}
+
+// run delivers queued alerts in batches until the queue is closed and drained.
+func (e *asyncEmitter) run() {
+	defer close(e.done)
+
+	for alert := range e.queue {
+		batch := []*Alert{alert}
+	drain:
+		for len(batch) < maxBatchSize {
+			select {
+			case alert, ok := <-e.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, alert)
+			default:
+				break drain
+			}
+		}
+		e.observeQueue()
+		e.send(batch)
+	}
+}
+
+func (e *asyncEmitter) send(batch []*Alert) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.sendTimeout)
+	defer cancel()
+
+	resp, err := e.am.EmitContext(ctx, batch...)
+	if err != nil {
+		e.log.Error(err, "failed to send queued alerts", logKeyAlertCount, len(batch))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		e.log.Error(errors.New(resp.Status), "Alertmanager rejected queued alerts",
+			logKeyAlertCount, len(batch), logKeyStatus, resp.StatusCode)
+	}
+}
+
+// close stops accepting alerts and waits until all queued alerts have been delivered
+// or ctx is done.
+func (e *asyncEmitter) close(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drop records dropped alerts. The metrics are resolved on every call, since the client of
+// a ReloadableAlertmanager and its metrics may change.
+func (e *asyncEmitter) drop(n int) {
+	if metrics := emitterMetrics(e.am); metrics != nil {
+		metrics.AddDropped(n)
+	}
+}
+
+func (e *asyncEmitter) observeQueue() {
+	if metrics := emitterMetrics(e.am); metrics != nil {
+		metrics.SetQueue(e.cfg.queueName, len(e.queue), cap(e.queue))
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+// alertInput is an alert read from a JSON or YAML input file.
+type alertInput struct {
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+	StartsAt    string            `yaml:"startsAt"`
+	EndsAt      string            `yaml:"endsAt"`
+}
+
+func runFire(ctx context.Context, c *cli, args []string) error {
+	return sendAlerts(ctx, c, args, false)
+}
+
+func runResolve(ctx context.Context, c *cli, args []string) error {
+	return sendAlerts(ctx, c, args, true)
+}
+
+// sendAlerts sends the alerts read from the input file, if any, or a single alert built from
+// the label arguments. Labels and annotations from arguments are added to every alert.
+func sendAlerts(ctx context.Context, c *cli, args []string, resolve bool) error {
+	fs := c.flagSet()
+	annotations := keyValues{}
+	fs.Var(annotations, "a", "Annotation as key=value (repeatable)")
+	file := fs.String("f", "", "JSON or YAML file with an alert or list of alerts, or - for stdin")
+	startsAt := fs.String("starts-at", "", "Start time of the alerts (RFC 3339, default now)")
+	endsAt := fs.String("ends-at", "", "End time of the alerts (RFC 3339)")
+	duration := fs.Duration("duration", 0, "Duration after which the alerts resolve, instead of --ends-at")
+
+	args, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{}
+	for _, arg := range args {
+		k, v, err := parseKeyValue(arg)
+		if err != nil {
+			return err
+		}
+		labels[k] = v
+	}
+
+	var inputs []alertInput
+	if *file != "" {
+		if inputs, err = readAlerts(c.stdin, *file); err != nil {
+			return err
+		}
+	} else if len(labels) > 0 {
+		inputs = []alertInput{{}}
+	}
+	if len(inputs) == 0 {
+		return errors.New("no alerts: pass labels as label=value arguments or an input file with -f")
+	}
+
+	now := time.Now()
+	alerts := make([]*alertmanager.Alert, 0, len(inputs))
+	for i, in := range inputs {
+		alert := alertmanager.NewAlert()
+		maps.Copy(alert.Labels, in.Labels)
+		maps.Copy(alert.Labels, labels)
+		maps.Copy(alert.Annotations, in.Annotations)
+		maps.Copy(alert.Annotations, annotations)
+
+		start, err := parseTime(firstNonEmpty(*startsAt, in.StartsAt))
+		if err != nil {
+			return fmt.Errorf("alert %d: invalid start time: %w", i, err)
+		}
+		end, err := parseTime(firstNonEmpty(*endsAt, in.EndsAt))
+		if err != nil {
+			return fmt.Errorf("alert %d: invalid end time: %w", i, err)
+		}
+		if *duration > 0 {
+			end = now.Add(*duration)
+		}
+		if resolve {
+			end = now
+		}
+
+		if !start.IsZero() {
+			alertmanager.WithStartsAt(start)(alert)
+		}
+		if !end.IsZero() {
+			if !start.IsZero() && end.Before(start) {
+				end = start
+			}
+			alertmanager.WithEndsAt(end)(alert)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	am, err := c.client()
+	if err != nil {
+		return err
+	}
+	resp, err := am.EmitContext(ctx, alerts...)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	verb := "sent"
+	if resolve {
+		verb = "resolved"
+	}
+	fmt.Fprintf(c.stdout, "%s %d alert(s)\n", verb, len(alerts))
+	return nil
+}
+
+// readAlerts reads a single alert or a list of alerts in JSON or YAML from path, or from stdin if path is -.
+func readAlerts(stdin io.Reader, path string) ([]alertInput, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerts: %w", err)
+	}
+
+	// JSON is valid YAML, so a YAML decoder reads both formats
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	// a list of alerts and a single alert are decoded alike, rejecting unknown fields
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if doc.Content[0].Kind == yaml.SequenceNode {
+		var alerts []alertInput
+		if err := dec.Decode(&alerts); err != nil {
+			return nil, fmt.Errorf("failed to parse alerts: %w", err)
+		}
+		return alerts, nil
+	}
+
+	var alert alertInput
+	if err := dec.Decode(&alert); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts: %w", err)
+	}
+	return []alertInput{alert}, nil
+}
+
+func runListAlerts(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet()
+	output := fs.String("o", outputTable, "Output format: table or json")
+
+	filter, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	am, err := c.client()
+	if err != nil {
+		return err
+	}
+	alerts, err := am.ListAlerts(ctx, filter...)
+	if err != nil {
+		return err
+	}
+
+	if *output == outputJSON {
+		return writeJSON(c.stdout, alerts)
+	}
+
+	rows := make([][]string, 0, len(alerts))
+	for _, alert := range alerts {
+		rows = append(rows, []string{
+			alert.Labels["alertname"],
+			alert.Status.State,
+			formatTime(alert.StartsAt),
+			formatLabels(alert.Labels, "alertname"),
+			alert.Annotations["summary"],
+		})
+	}
+	return writeTable(c.stdout, []string{"ALERTNAME", "STATE", "STARTS AT", "LABELS", "SUMMARY"}, rows)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(s))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
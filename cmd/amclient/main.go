@@ -0,0 +1,202 @@
+// Command amclient fires, resolves, lists and silences Alertmanager alerts.
+//
+// Usage:
+//
+//	amclient <command> [flags] [arguments]
+//
+// Every command accepts the --alertmanager-* flags of alertmanager.Args, which can also be set
+// by ALERTMANAGER_* environment variables, e.g. ALERTMANAGER_URL. For example:
+//
+//	amclient fire --alertmanager-url http://localhost:9093 -a summary="Disk almost full" alertname=DiskFull severity=warning
+//	amclient alerts --alertmanager-url http://localhost:9093 -o json 'severity="critical"'
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+// command is a subcommand of amclient.
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = []command{
+	{name: "fire", usage: "[flags] [label=value ...]", description: "Send firing alerts", run: runFire},
+	{name: "resolve", usage: "[flags] [label=value ...]", description: "Send resolved alerts", run: runResolve},
+	{name: "alerts", usage: "[flags] [matcher ...]", description: "List alerts matching all matchers", run: runListAlerts},
+	{name: "silence", usage: "[flags] matcher ...", description: "Create a silence for alerts matching all matchers", run: runSilence},
+	{name: "silences", usage: "[flags]", description: "List silences", run: runListSilences},
+	{name: "expire", usage: "[flags] silence-id ...", description: "Expire silences", run: runExpire},
+}
+
+// errUsage is returned when a command is invoked incorrectly; the usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+// cli holds the state shared by all commands.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	command command
+	args    alertmanager.Args
+	verbose bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes amclient with the given arguments and returns the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		c.usage()
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		c.command = cmd
+		err := cmd.run(ctx, c, args[1:])
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		case err != nil:
+			fmt.Fprintf(stderr, "amclient %s: %v\n", cmd.name, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "amclient: unknown command %q\n", args[0])
+	c.usage()
+	return 2
+}
+
+func (c *cli) usage() {
+	fmt.Fprintf(c.stderr, "Usage: amclient <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(c.stderr, "\nRun 'amclient <command> -h' for the flags of a command.\n")
+}
+
+// flagSet returns a flag set for the current command that includes the Alertmanager client flags.
+func (c *cli) flagSet() *flag.FlagSet {
+	cmd := c.command
+	fs := flag.NewFlagSet("amclient "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: amclient %s %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.usage, cmd.description)
+		fs.PrintDefaults()
+	}
+
+	c.args.BindFlags(fs)
+	fs.BoolVar(&c.verbose, "v", false, "Log requests to stderr")
+	return fs
+}
+
+// parse parses the flags of a command and returns its arguments.
+// The Alertmanager client flags default to their ALERTMANAGER_* environment variables.
+func (c *cli) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	if err := c.args.BindEnv(""); err != nil {
+		return nil, err
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
+
+// client creates an Alertmanager client from the parsed flags.
+func (c *cli) client() (*alertmanager.Alertmanager, error) {
+	// dry runs log the payload that would be sent, so they always log to stderr
+	logger := logr.Discard()
+	if c.verbose || c.args.DryRun {
+		verbosity := 0
+		if c.verbose {
+			verbosity = 1
+		}
+		logger = funcr.New(func(prefix, args string) {
+			fmt.Fprintln(c.stderr, args)
+		}, funcr.Options{Verbosity: verbosity})
+	}
+
+	args := c.args
+	args.Enabled = true
+	if args.AlertmanagerURL == "" {
+		return nil, errors.New("--alertmanager-url is required")
+	}
+	return alertmanager.NewAlertmanagerWithArgs(logger, args)
+}
+
+// checkResponse returns an error if Alertmanager rejected a request.
+func checkResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("alertmanager responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// keyValues is a repeatable flag of key=value pairs.
+type keyValues map[string]string
+
+func (kv keyValues) String() string {
+	pairs := make([]string, 0, len(kv))
+	for k, v := range kv {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (kv keyValues) Set(s string) error {
+	k, v, err := parseKeyValue(s)
+	if err != nil {
+		return err
+	}
+	kv[k] = v
+	return nil
+}
+
+func parseKeyValue(s string) (string, string, error) {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return "", "", fmt.Errorf("invalid key=value pair %q", s)
+	}
+	return k, v, nil
+}
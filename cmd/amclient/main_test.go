@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+	"github.com/spectrocloud-labs/alertmanager-client-go/alertmanagertest"
+)
+
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestFireAndResolve(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "alerts.yaml")
+	err := os.WriteFile(yamlFile, []byte(`
+- labels:
+    alertname: DiskFull
+    instance: web-1
+  annotations:
+    summary: Disk almost full
+- labels:
+    alertname: DiskFull
+    instance: web-2
+  endsAt: 2030-01-01T00:00:00Z
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write alerts file: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		stdin            string
+		args             []string
+		expectedCode     int
+		expectedAlerts   int
+		expectedMatchers []alertmanagertest.Matcher
+	}{
+		{
+			name:           "labels and annotations from arguments",
+			args:           []string{"fire", "-a", "summary=Test alert", "alertname=Test", "severity=warning"},
+			expectedCode:   0,
+			expectedAlerts: 1,
+			expectedMatchers: []alertmanagertest.Matcher{
+				alertmanagertest.HasLabel("alertname", "Test"),
+				alertmanagertest.HasLabel("severity", "warning"),
+				alertmanagertest.HasAnnotation("summary", "Test alert"),
+				alertmanagertest.Firing(),
+			},
+		},
+		{
+			name:           "YAML file with extra labels",
+			args:           []string{"fire", "-f", yamlFile, "team=storage"},
+			expectedCode:   0,
+			expectedAlerts: 2,
+			expectedMatchers: []alertmanagertest.Matcher{
+				alertmanagertest.HasLabel("instance", "web-1"),
+				alertmanagertest.HasLabel("team", "storage"),
+				alertmanagertest.HasAnnotation("summary", "Disk almost full"),
+			},
+		},
+		{
+			name:           "JSON from stdin",
+			stdin:          `{"labels": {"alertname": "FromStdin"}, "annotations": {"summary": "piped"}}`,
+			args:           []string{"fire", "-f", "-"},
+			expectedCode:   0,
+			expectedAlerts: 1,
+			expectedMatchers: []alertmanagertest.Matcher{
+				alertmanagertest.HasLabel("alertname", "FromStdin"),
+				alertmanagertest.HasAnnotation("summary", "piped"),
+			},
+		},
+		{
+			name:         "unknown field in list",
+			stdin:        `[{"labels": {"alertname": "Typo"}, "annotation": {"summary": "typo"}}]`,
+			args:         []string{"fire", "-f", "-"},
+			expectedCode: 1,
+		},
+		{
+			name:         "unknown field in single alert",
+			stdin:        `{"labels": {"alertname": "Typo"}, "annotation": {"summary": "typo"}}`,
+			args:         []string{"fire", "-f", "-"},
+			expectedCode: 1,
+		},
+		{
+			name:           "resolve",
+			args:           []string{"resolve", "alertname=Test"},
+			expectedCode:   0,
+			expectedAlerts: 1,
+			expectedMatchers: []alertmanagertest.Matcher{
+				alertmanagertest.HasLabel("alertname", "Test"),
+				alertmanagertest.Resolved(),
+			},
+		},
+		{
+			name:         "no alerts",
+			args:         []string{"fire"},
+			expectedCode: 1,
+		},
+		{
+			name:         "invalid label argument",
+			args:         []string{"fire", "alertname"},
+			expectedCode: 1,
+		},
+		{
+			name:         "unknown flag",
+			args:         []string{"fire", "--unknown", "alertname=Test"},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := alertmanagertest.NewServer(t)
+
+			args := append([]string{tt.args[0], "--alertmanager-url", server.URL}, tt.args[1:]...)
+			code, _, stderr := runCLI(t, tt.stdin, args...)
+			if code != tt.expectedCode {
+				t.Fatalf("expected exit code %d, got %d: %s", tt.expectedCode, code, stderr)
+			}
+			if len(server.Alerts()) != tt.expectedAlerts {
+				t.Errorf("expected %d alerts, got %d", tt.expectedAlerts, len(server.Alerts()))
+			}
+			if len(tt.expectedMatchers) > 0 {
+				server.RequireAlert(t, tt.expectedMatchers...)
+			}
+		})
+	}
+}
+
+func TestListAlerts(t *testing.T) {
+	server := alertmanagertest.NewServer(t, alertmanagertest.WithBasicAuth("user", "pass"))
+	auth := []string{"--alertmanager-url", server.URL, "--alertmanager-username", "user", "--alertmanager-password", "pass"}
+
+	for _, labels := range [][]string{{"alertname=DiskFull", "severity=critical"}, {"alertname=HighLatency", "severity=warning"}} {
+		if code, _, stderr := runCLI(t, "", append(append([]string{"fire"}, auth...), labels...)...); code != 0 {
+			t.Fatalf("failed to fire alert: %s", stderr)
+		}
+	}
+
+	code, stdout, stderr := runCLI(t, "", append(append([]string{"alerts"}, auth...), `severity="critical"`)...)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "ALERTNAME") || !strings.Contains(stdout, "DiskFull") || strings.Contains(stdout, "HighLatency") {
+		t.Errorf("expected table with DiskFull only, got:\n%s", stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, "", append(append([]string{"alerts"}, auth...), "-o", "json")...)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+	var alerts []alertmanager.GettableAlert
+	if err := json.Unmarshal([]byte(stdout), &alerts); err != nil {
+		t.Fatalf("failed to decode JSON output: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Errorf("expected 2 alerts, got %d", len(alerts))
+	}
+
+	code, _, _ = runCLI(t, "", "alerts", "--alertmanager-url", server.URL)
+	if code != 1 {
+		t.Errorf("expected exit code 1 without credentials, got %d", code)
+	}
+
+	t.Setenv("ALERTMANAGER_URL", server.URL)
+	t.Setenv("ALERTMANAGER_USERNAME", "user")
+	t.Setenv("ALERTMANAGER_PASSWORD", "pass")
+	code, _, stderr = runCLI(t, "", "alerts")
+	if code != 0 {
+		t.Errorf("expected exit code 0 with credentials from the environment, got %d: %s", code, stderr)
+	}
+}
+
+func TestSilences(t *testing.T) {
+	server := alertmanagertest.NewServer(t)
+	url := []string{"--alertmanager-url", server.URL}
+
+	code, stdout, stderr := runCLI(t, "", append(append([]string{"silence"}, url...),
+		"-comment", "maintenance", "-author", "ops", "-duration", "2h", "alertname=DiskFull", `instance=~"web-.*"`)...)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+	id := strings.TrimSpace(stdout)
+
+	silences := server.Silences()
+	if len(silences) != 1 || silences[0].ID != id {
+		t.Fatalf("expected silence %s, got %+v", id, silences)
+	}
+	if len(silences[0].Matchers) != 2 || !silences[0].Matchers[1].IsRegex || silences[0].CreatedBy != "ops" {
+		t.Errorf("unexpected silence %+v", silences[0])
+	}
+	if d := silences[0].EndsAt.Sub(silences[0].StartsAt); d != 2*time.Hour {
+		t.Errorf("expected duration 2h, got %s", d)
+	}
+
+	code, stdout, _ = runCLI(t, "", append([]string{"silences"}, url...)...)
+	if code != 0 || !strings.Contains(stdout, id) || !strings.Contains(stdout, `instance=~"web-.*"`) {
+		t.Errorf("expected silence in table, got %d:\n%s", code, stdout)
+	}
+
+	code, stdout, stderr = runCLI(t, "", append(append([]string{"silence", "--alertmanager-dry-run"}, url...),
+		"-comment", "maintenance", "alertname=DryRun")...)
+	if code != 0 || stdout != "" || !strings.Contains(stderr, "dry run") {
+		t.Errorf("expected dry run to log the silence, got %d:\n%s\n%s", code, stdout, stderr)
+	}
+	if len(server.Silences()) != 1 {
+		t.Errorf("expected dry run not to create a silence, got %d silences", len(server.Silences()))
+	}
+
+	code, stdout, stderr = runCLI(t, "", append(append([]string{"expire", "--alertmanager-dry-run"}, url...), id)...)
+	if code != 0 || stdout != "" {
+		t.Fatalf("expected exit code 0 without output, got %d:\n%s\n%s", code, stdout, stderr)
+	}
+	if state := server.Silences()[0].Status.State; state != "active" {
+		t.Errorf("expected dry run not to expire the silence, got %s", state)
+	}
+
+	code, _, stderr = runCLI(t, "", append(append([]string{"expire"}, url...), id)...)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+	if state := server.Silences()[0].Status.State; state != "expired" {
+		t.Errorf("expected silence to be expired, got %s", state)
+	}
+
+	code, stdout, _ = runCLI(t, "", append([]string{"silences"}, url...)...)
+	if code != 0 || strings.Contains(stdout, id) {
+		t.Errorf("expected expired silence to be hidden, got %d:\n%s", code, stdout)
+	}
+
+	if code, _, _ := runCLI(t, "", append(append([]string{"silence"}, url...), "alertname=DiskFull")...); code != 1 {
+		t.Errorf("expected exit code 1 without comment, got %d", code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		args         []string
+		expectedCode int
+	}{
+		{args: nil, expectedCode: 2},
+		{args: []string{"help"}, expectedCode: 0},
+		{args: []string{"unknown"}, expectedCode: 2},
+		{args: []string{"fire", "-h"}, expectedCode: 0},
+		{args: []string{"alerts"}, expectedCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			code, _, stderr := runCLI(t, "", tt.args...)
+			if code != tt.expectedCode {
+				t.Errorf("expected exit code %d, got %d: %s", tt.expectedCode, code, stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spectrocloud-labs/alertmanager-client-go"
+)
+
+func runSilence(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet()
+	duration := fs.Duration("duration", time.Hour, "Duration of the silence")
+	comment := fs.String("comment", "", "Reason for the silence (required)")
+	author := fs.String("author", os.Getenv("USER"), "Author of the silence")
+
+	args, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("at least one matcher required, e.g. alertname=DiskFull")
+	}
+	if *comment == "" {
+		return errors.New("--comment is required")
+	}
+	if *duration <= 0 {
+		return errors.New("--duration must be positive")
+	}
+
+	silence := alertmanager.Silence{
+		CreatedBy: *author,
+		Comment:   *comment,
+		StartsAt:  time.Now(),
+	}
+	silence.EndsAt = silence.StartsAt.Add(*duration)
+	for _, arg := range args {
+		m, err := alertmanager.ParseSilenceMatcher(arg)
+		if err != nil {
+			return err
+		}
+		silence.Matchers = append(silence.Matchers, m)
+	}
+
+	am, err := c.client()
+	if err != nil {
+		return err
+	}
+	id, err := am.CreateSilence(ctx, silence)
+	if err != nil {
+		return err
+	}
+
+	// dry runs log the silence instead of creating it, so there is no ID
+	if !c.args.DryRun {
+		fmt.Fprintln(c.stdout, id)
+	}
+	return nil
+}
+
+func runListSilences(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet()
+	output := fs.String("o", outputTable, "Output format: table or json")
+	expired := fs.Bool("expired", false, "Include expired silences")
+
+	if _, err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	am, err := c.client()
+	if err != nil {
+		return err
+	}
+	all, err := am.ListSilences(ctx)
+	if err != nil {
+		return err
+	}
+
+	silences := make([]alertmanager.Silence, 0, len(all))
+	for _, s := range all {
+		if *expired || s.Status == nil || s.Status.State != "expired" {
+			silences = append(silences, s)
+		}
+	}
+
+	if *output == outputJSON {
+		return writeJSON(c.stdout, silences)
+	}
+
+	rows := make([][]string, 0, len(silences))
+	for _, s := range silences {
+		state := ""
+		if s.Status != nil {
+			state = s.Status.State
+		}
+		matchers := make([]string, 0, len(s.Matchers))
+		for _, m := range s.Matchers {
+			matchers = append(matchers, m.String())
+		}
+		rows = append(rows, []string{
+			s.ID,
+			state,
+			formatTime(&s.EndsAt),
+			s.CreatedBy,
+			strings.Join(matchers, " "),
+			s.Comment,
+		})
+	}
+	return writeTable(c.stdout, []string{"ID", "STATE", "ENDS AT", "CREATED BY", "MATCHERS", "COMMENT"}, rows)
+}
+
+func runExpire(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet()
+
+	ids, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return errors.New("at least one silence ID required")
+	}
+
+	am, err := c.client()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := am.ExpireSilence(ctx, id); err != nil {
+			return err
+		}
+		if !c.args.DryRun {
+			fmt.Fprintf(c.stdout, "expired %s\n", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package alertmanager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultConfigTimeout is the default request timeout of clients created from a Config, as in Prometheus.
+const DefaultConfigTimeout = 10 * time.Second
+
+// Config is the configuration of an Alertmanager client read from a YAML or JSON file.
+// It is compatible with an entry of the alertmanagers block of the Prometheus configuration,
+// limited to a single static target.
+type Config struct {
+	// Scheme is http or https. Defaults to http.
+	Scheme string `yaml:"scheme,omitempty"`
+
+	// PathPrefix is the path under which the Alertmanager API is served.
+	PathPrefix string `yaml:"path_prefix,omitempty"`
+
+	// Timeout is the timeout for requests to Alertmanager. Defaults to DefaultConfigTimeout.
+	Timeout model.Duration `yaml:"timeout,omitempty"`
+
+	// APIVersion must be v2 if set.
+	APIVersion string `yaml:"api_version,omitempty"`
+
+	// StaticConfigs must contain exactly one target in host:port form.
+	StaticConfigs []StaticConfig `yaml:"static_configs"`
+
+	BasicAuth     *BasicAuthConfig     `yaml:"basic_auth,omitempty"`
+	Authorization *AuthorizationConfig `yaml:"authorization,omitempty"`
+	OAuth2        *OAuth2              `yaml:"oauth2,omitempty"`
+	TLSConfig     TLSConfig            `yaml:"tls_config,omitempty"`
+
+	ProxyURL             string `yaml:"proxy_url,omitempty"`
+	NoProxy              string `yaml:"no_proxy,omitempty"`
+	ProxyFromEnvironment bool   `yaml:"proxy_from_environment,omitempty"`
+
+	// FollowRedirects defaults to true. If false, redirect responses are returned instead of followed.
+	FollowRedirects *bool `yaml:"follow_redirects,omitempty"`
+
+	// EnableHTTP2 is accepted for compatibility with Prometheus and ignored.
+	EnableHTTP2 *bool `yaml:"enable_http2,omitempty"`
+
+	// RelabelConfigs and AlertRelabelConfigs are not supported. They are decoded only so that
+	// Validate can reject them with a clear error, rather than routing alerts differently than configured.
+	RelabelConfigs      []map[string]any `yaml:"relabel_configs,omitempty"`
+	AlertRelabelConfigs []map[string]any `yaml:"alert_relabel_configs,omitempty"`
+
+	// dir is the directory relative to which the _file fields are resolved.
+	dir string
+}
+
+// StaticConfig is a list of statically configured Alertmanager targets.
+// Labels are accepted for compatibility with Prometheus and ignored.
+type StaticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+// BasicAuthConfig configures basic authentication.
+type BasicAuthConfig struct {
+	Username     string `yaml:"username,omitempty"`
+	UsernameFile string `yaml:"username_file,omitempty"`
+	Password     string `yaml:"password,omitempty"`
+	PasswordFile string `yaml:"password_file,omitempty"`
+}
+
+// AuthorizationConfig configures the Authorization header, e.g. a bearer token.
+type AuthorizationConfig struct {
+	// Type defaults to Bearer.
+	Type            string `yaml:"type,omitempty"`
+	Credentials     string `yaml:"credentials,omitempty"`
+	CredentialsFile string `yaml:"credentials_file,omitempty"`
+}
+
+// TLSConfig configures TLS connections to Alertmanager. Certificates and keys are PEM-encoded.
+type TLSConfig struct {
+	CA                 string `yaml:"ca,omitempty"`
+	CAFile             string `yaml:"ca_file,omitempty"`
+	Cert               string `yaml:"cert,omitempty"`
+	CertFile           string `yaml:"cert_file,omitempty"`
+	Key                string `yaml:"key,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty"`
+	ServerName         string `yaml:"server_name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+
+	// MinVersion and MaxVersion are TLS12 or TLS13.
+	MinVersion string `yaml:"min_version,omitempty"`
+	MaxVersion string `yaml:"max_version,omitempty"`
+}
+
+// LoadConfig reads, parses and validates a YAML or JSON config file.
+// Relative paths in _file fields are resolved relative to the directory of the file.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Alertmanager config: %w", err)
+	}
+
+	cfg, err := ParseConfig(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Alertmanager config %s: %w", path, err)
+	}
+	cfg.dir = filepath.Dir(path)
+	return cfg, nil
+}
+
+// ParseConfig parses and validates a YAML or JSON config. All validation errors are returned at once.
+func ParseConfig(content []byte) (*Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse Alertmanager config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks the config for missing, conflicting and invalid fields.
+// All problems are returned at once as a joined error.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	exclusive := func(field1, value1, field2, value2 string) {
+		if value1 != "" && value2 != "" {
+			add("%s and %s are mutually exclusive", field1, field2)
+		}
+	}
+
+	if c.Scheme != "" && c.Scheme != "http" && c.Scheme != "https" {
+		add("scheme: must be http or https, got %q", c.Scheme)
+	}
+	if c.APIVersion != "" && c.APIVersion != "v2" {
+		add("api_version: only v2 is supported, got %q", c.APIVersion)
+	}
+	if len(c.RelabelConfigs) > 0 {
+		add("relabel_configs: unsupported, alerts cannot be relabeled")
+	}
+	if len(c.AlertRelabelConfigs) > 0 {
+		add("alert_relabel_configs: unsupported, alerts cannot be relabeled")
+	}
+	if c.Timeout < 0 {
+		add("timeout: must not be negative")
+	}
+
+	var targets []string
+	for _, sc := range c.StaticConfigs {
+		targets = append(targets, sc.Targets...)
+	}
+	if len(targets) != 1 {
+		add("static_configs: exactly one target required, got %d", len(targets))
+	}
+	for i, target := range targets {
+		if target == "" || strings.Contains(target, "/") {
+			add("static_configs: target %d: must be host:port, got %q", i, target)
+		}
+	}
+
+	auth := 0
+	if c.BasicAuth != nil {
+		auth++
+		exclusive("basic_auth.username", c.BasicAuth.Username, "basic_auth.username_file", c.BasicAuth.UsernameFile)
+		exclusive("basic_auth.password", c.BasicAuth.Password, "basic_auth.password_file", c.BasicAuth.PasswordFile)
+		if c.BasicAuth.Username == "" && c.BasicAuth.UsernameFile == "" {
+			add("basic_auth.username: required")
+		}
+	}
+	if c.Authorization != nil {
+		auth++
+		exclusive("authorization.credentials", c.Authorization.Credentials, "authorization.credentials_file", c.Authorization.CredentialsFile)
+		if strings.EqualFold(c.Authorization.Type, "basic") {
+			add("authorization.type: use basic_auth instead of %q", c.Authorization.Type)
+		}
+	}
+	if c.OAuth2 != nil {
+		auth++
+		exclusive("oauth2.client_secret", c.OAuth2.ClientSecret, "oauth2.client_secret_file", c.OAuth2.ClientSecretFile)
+		if c.OAuth2.ClientID == "" {
+			add("oauth2.client_id: required")
+		}
+		if u, err := url.Parse(c.OAuth2.TokenURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("oauth2.token_url: must be an absolute URL, got %q", redactURL(c.OAuth2.TokenURL))
+		}
+	}
+	if auth > 1 {
+		add("at most one of basic_auth, authorization and oauth2 may be set")
+	}
+
+	tc := c.TLSConfig
+	exclusive("tls_config.ca", tc.CA, "tls_config.ca_file", tc.CAFile)
+	exclusive("tls_config.cert", tc.Cert, "tls_config.cert_file", tc.CertFile)
+	exclusive("tls_config.key", tc.Key, "tls_config.key_file", tc.KeyFile)
+	if (tc.Cert != "" || tc.CertFile != "") != (tc.Key != "" || tc.KeyFile != "") {
+		add("tls_config: client certificate and key must be set together")
+	}
+	errs = append(errs, validateTLSVersions("tls_config.min_version", tc.MinVersion, "tls_config.max_version", tc.MaxVersion)...)
+	errs = append(errs, validateProxy("proxy_url", c.ProxyURL, c.ProxyFromEnvironment, "no_proxy", c.NoProxy)...)
+
+	return errors.Join(errs...)
+}
+
+// endpoint returns the scheme and host of the configured Alertmanager.
+func (c *Config) endpoint() string {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	var target string
+	for _, sc := range c.StaticConfigs {
+		if len(sc.Targets) > 0 {
+			target = sc.Targets[0]
+			break
+		}
+	}
+	return scheme + "://" + target
+}
+
+// NewAlertmanagerFromConfig creates an Alertmanager client configured by cfg.
+// Secrets in _file fields are read when the client is created; basic auth password and authorization
+// credentials files are also re-read when they change. Additional options are applied
+// after those derived from the config.
+func NewAlertmanagerFromConfig(logger logr.Logger, cfg *Config, options ...ManagerOption) (*Alertmanager, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Alertmanager config: %w", err)
+	}
+
+	opts, err := cfg.options()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+	}
+	if cfg.FollowRedirects != nil && !*cfg.FollowRedirects {
+		httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return NewAlertmanager(logger, httpClient, append(opts, options...)...)
+}
+
+// options converts the config into ManagerOptions, reading secrets from files.
+func (c *Config) options() ([]ManagerOption, error) {
+	timeout := time.Duration(c.Timeout)
+	if timeout == 0 {
+		timeout = DefaultConfigTimeout
+	}
+
+	opts := []ManagerOption{
+		WithEndpoint(c.endpoint()),
+		WithPathPrefix(c.PathPrefix),
+		WithTimeout(timeout),
+	}
+
+	if ba := c.BasicAuth; ba != nil {
+		username, err := c.secret(ba.Username, ba.UsernameFile)
+		if err != nil {
+			return nil, err
+		}
+		if ba.PasswordFile != "" {
+			opts = append(opts, WithBasicAuthFile(username, c.path(ba.PasswordFile)))
+		} else {
+			opts = append(opts, WithBasicAuth(username, ba.Password))
+		}
+	}
+
+	if authz := c.Authorization; authz != nil {
+		if authz.CredentialsFile != "" {
+			opts = append(opts, WithAuthorizationFile(authz.Type, c.path(authz.CredentialsFile)))
+		} else {
+			opts = append(opts, WithAuthorization(authz.Type, authz.Credentials))
+		}
+	}
+
+	if c.OAuth2 != nil {
+		oauth := *c.OAuth2
+		if oauth.ClientSecretFile != "" {
+			oauth.ClientSecretFile = c.path(oauth.ClientSecretFile)
+		}
+		opts = append(opts, WithOAuth2(oauth))
+	}
+
+	tlsOpts, err := c.tlsOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, tlsOpts...)
+
+	if c.ProxyURL != "" {
+		opts = append(opts, WithProxyURL(c.ProxyURL))
+	}
+	if c.NoProxy != "" {
+		opts = append(opts, WithNoProxy(c.NoProxy))
+	}
+	if c.ProxyFromEnvironment {
+		opts = append(opts, WithProxyFromEnvironment())
+	}
+
+	return opts, nil
+}
+
+func (c *Config) tlsOptions() ([]ManagerOption, error) {
+	tc := c.TLSConfig
+	var opts []ManagerOption
+
+	ca, err := c.secret(tc.CA, tc.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	if ca != "" {
+		opts = append(opts, WithCustomCA([]byte(ca)))
+	}
+
+	cert, err := c.secret(tc.Cert, tc.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.secret(tc.Key, tc.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if cert != "" {
+		opts = append(opts, WithClientCertificate([]byte(cert), []byte(key)))
+	}
+
+	if tc.ServerName != "" {
+		opts = append(opts, WithServerName(tc.ServerName))
+	}
+	if tc.InsecureSkipVerify {
+		opts = append(opts, WithInsecure(true))
+	}
+	if tc.MinVersion != "" {
+		minVersion, _ := stringToSecureTLSVersion(tc.MinVersion)
+		opts = append(opts, WithMinTLSVersion(minVersion))
+	}
+	if tc.MaxVersion != "" {
+		maxVersion, _ := stringToSecureTLSVersion(tc.MaxVersion)
+		opts = append(opts, WithMaxTLSVersion(maxVersion))
+	}
+
+	return opts, nil
+}
+
+// secret returns value, or the content of file if set.
+func (c *Config) secret(value, file string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+	return readSecretFile(c.path(file))
+}
+
+// path resolves a path relative to the directory of the config file.
+func (c *Config) path(file string) string {
+	if c.dir == "" || filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(c.dir, file)
+}
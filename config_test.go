@@ -0,0 +1,312 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name               string
+		content            string
+		expectedEndpoint   string
+		expectedPathPrefix string
+		expectedTimeout    time.Duration
+		expectedErrors     []string
+	}{
+		{
+			name: "yaml",
+			content: `
+scheme: https
+path_prefix: /alertmanager
+timeout: 5s
+api_version: v2
+static_configs:
+  - targets: ["alertmanager:9093"]
+basic_auth:
+  username: admin
+  password: secret
+tls_config:
+  insecure_skip_verify: true
+  min_version: TLS13
+`,
+			expectedEndpoint:   "https://alertmanager:9093",
+			expectedPathPrefix: "/alertmanager",
+			expectedTimeout:    5 * time.Second,
+		},
+		{
+			name:             "json",
+			content:          `{"static_configs": [{"targets": ["localhost:9093"]}], "authorization": {"credentials": "token"}}`,
+			expectedEndpoint: "http://localhost:9093",
+			expectedTimeout:  DefaultConfigTimeout,
+		},
+		{
+			name: "prometheus fields",
+			content: `
+follow_redirects: false
+enable_http2: true
+static_configs:
+  - targets: ["alertmanager:9093"]
+`,
+			expectedEndpoint: "http://alertmanager:9093",
+			expectedTimeout:  DefaultConfigTimeout,
+		},
+		{
+			name: "invalid config",
+			content: `
+scheme: ftp
+api_version: v1
+static_configs:
+  - targets: ["a:9093", "b:9093"]
+basic_auth:
+  password: secret
+  password_file: password
+authorization:
+  type: Basic
+oauth2:
+  client_secret: secret
+tls_config:
+  cert_file: cert.pem
+  max_version: TLS10
+no_proxy: localhost
+`,
+			expectedErrors: []string{
+				"scheme: must be http or https",
+				"api_version: only v2 is supported",
+				"static_configs: exactly one target required, got 2",
+				"basic_auth.password and basic_auth.password_file are mutually exclusive",
+				"basic_auth.username: required",
+				"authorization.type: use basic_auth",
+				"oauth2.client_id: required",
+				"oauth2.token_url: must be an absolute URL",
+				"at most one of basic_auth, authorization and oauth2 may be set",
+				"tls_config: client certificate and key must be set together",
+				"tls_config.max_version",
+				"no_proxy: requires proxy_url",
+			},
+		},
+		{
+			name:           "unknown field",
+			content:        "static_configs: [{targets: [localhost:9093]}]\nbearer_token: token\n",
+			expectedErrors: []string{"field bearer_token not found"},
+		},
+		{
+			name:           "inconsistent TLS range",
+			content:        "static_configs: [{targets: [localhost:9093]}]\ntls_config: {min_version: TLS13, max_version: TLS12}\n",
+			expectedErrors: []string{"tls_config.min_version: TLS13 is greater than tls_config.max_version TLS12"},
+		},
+		{
+			name:           "relabel configs",
+			content:        "static_configs: [{targets: [localhost:9093]}]\nrelabel_configs: [{regex: alertmanager, action: keep}]\n",
+			expectedErrors: []string{"relabel_configs: unsupported, alerts cannot be relabeled"},
+		},
+		{
+			name:           "alert relabel configs",
+			content:        "static_configs: [{targets: [localhost:9093]}]\nalert_relabel_configs: [{regex: replica, action: labeldrop}]\n",
+			expectedErrors: []string{"alert_relabel_configs: unsupported, alerts cannot be relabeled"},
+		},
+		{
+			name:           "target with scheme",
+			content:        "static_configs: [{targets: ['http://localhost:9093']}]\n",
+			expectedErrors: []string{"static_configs: target 0: must be host:port"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(tt.content))
+			if len(tt.expectedErrors) > 0 {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				for _, expected := range tt.expectedErrors {
+					if !strings.Contains(err.Error(), expected) {
+						t.Errorf("expected error to contain %q, got %v", expected, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if endpoint := cfg.endpoint(); endpoint != tt.expectedEndpoint {
+				t.Errorf("expected endpoint %q, got %q", tt.expectedEndpoint, endpoint)
+			}
+			if cfg.PathPrefix != tt.expectedPathPrefix {
+				t.Errorf("expected path prefix %q, got %q", tt.expectedPathPrefix, cfg.PathPrefix)
+			}
+
+			am, err := NewAlertmanagerFromConfig(logr.Discard(), cfg)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if am.client.Timeout != tt.expectedTimeout {
+				t.Errorf("expected timeout %v, got %v", tt.expectedTimeout, am.client.Timeout)
+			}
+		})
+	}
+}
+
+func TestNewAlertmanagerFromConfig(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse token request: %v", err)
+		}
+		if id, secret, _ := r.BasicAuth(); id != "client" || secret != "client-secret" {
+			t.Errorf("expected client credentials client:client-secret, got %s:%s", id, secret)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "oauth-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	tests := []struct {
+		name               string
+		config             string
+		files              map[string]string
+		expectedAuthHeader string
+		expectedPath       string
+	}{
+		{
+			name: "basic auth with password file",
+			config: `
+path_prefix: /am
+static_configs:
+  - targets: [%TARGET%]
+basic_auth:
+  username: admin
+  password_file: secrets/password
+`,
+			files:              map[string]string{"secrets/password": "secret\n"},
+			expectedAuthHeader: basicAuthHeader("admin", "secret"),
+			expectedPath:       "/am/api/v2/alerts",
+		},
+		{
+			name: "authorization with credentials file",
+			config: `
+static_configs:
+  - targets: [%TARGET%]
+authorization:
+  credentials_file: token
+`,
+			files:              map[string]string{"token": "bearer-token"},
+			expectedAuthHeader: "Bearer bearer-token",
+			expectedPath:       "/api/v2/alerts",
+		},
+		{
+			name: "oauth2",
+			config: `
+static_configs:
+  - targets: [%TARGET%]
+oauth2:
+  client_id: client
+  client_secret_file: client-secret
+  token_url: ` + tokenServer.URL + `
+`,
+			files:              map[string]string{"client-secret": "client-secret"},
+			expectedAuthHeader: "Bearer oauth-token",
+			expectedPath:       "/api/v2/alerts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authHeader, path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authHeader = r.Header.Get("Authorization")
+				path = r.URL.Path
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(server.Close)
+
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				filePath := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			u, _ := url.Parse(server.URL)
+			configPath := filepath.Join(dir, "alertmanager.yaml")
+			if err := os.WriteFile(configPath, []byte(strings.ReplaceAll(tt.config, "%TARGET%", u.Host)), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			am, err := NewAlertmanagerFromConfig(logr.Discard(), cfg)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			resp, err := am.EmitContext(context.Background(), NewAlert(WithLabel("alertname", "Test")))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if authHeader != tt.expectedAuthHeader {
+				t.Errorf("expected Authorization header %q, got %q", tt.expectedAuthHeader, authHeader)
+			}
+			if path != tt.expectedPath {
+				t.Errorf("expected path %q, got %q", tt.expectedPath, path)
+			}
+		})
+	}
+}
+
+func TestNewAlertmanagerFromConfigFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	cfg, err := ParseConfig([]byte("follow_redirects: false\nstatic_configs: [{targets: [" + u.Host + "]}]\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	am, err := NewAlertmanagerFromConfig(logr.Discard(), cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	resp, err := am.Emit(NewAlert(WithLabel("alertname", "Test")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("expected redirect response %d, got %d", http.StatusTemporaryRedirect, resp.StatusCode)
+	}
+}
+
+func TestNewAlertmanagerFromConfigMissingSecretFile(t *testing.T) {
+	cfg, err := ParseConfig([]byte("static_configs: [{targets: [localhost:9093]}]\nauthorization: {credentials_file: /nonexistent/token}\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := NewAlertmanagerFromConfig(logr.Discard(), cfg); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
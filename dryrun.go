@@ -0,0 +1,44 @@
+package alertmanager
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// dryRun holds the destination of payloads in dry-run mode. It is shared by clones of an Alertmanager.
+type dryRun struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithDryRun enables dry-run mode: alerts are merged with the base labels and annotations and templated
+// as usual, and validated as Alertmanager would validate them, returning ErrInvalidAlert for alerts it would
+// reject. The final JSON payload is written to w instead of being sent to Alertmanager, and a synthetic
+// 200 OK response is returned. If w is nil, the payload is logged at info level instead, with the values
+// of keys set by WithSensitiveKeys redacted. Silences are not created or expired in dry-run mode but
+// logged at info level, while read-only API calls such as ListAlerts are still sent.
+func WithDryRun(w io.Writer) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.dryRun = &dryRun{w: w}
+		return nil
+	}
+}
+
+// emitDryRun writes the payload of a request that would have been sent to Alertmanager.
+func (a *Alertmanager) emitDryRun(req *http.Request, body []byte, alerts []Alert) (*http.Response, error) {
+	if a.dryRun.w == nil {
+		a.log.Info("dry run: not sending alerts", logKeyEndpoint, redactURL(req.URL.String()), logKeyAlertCount, len(alerts),
+			logKeyPayload, a.logPayload(body, alerts))
+		return nopResponse(), nil
+	}
+
+	a.dryRun.mu.Lock()
+	defer a.dryRun.mu.Unlock()
+
+	if _, err := fmt.Fprintf(a.dryRun.w, "%s\n", body); err != nil {
+		return nil, fmt.Errorf("failed to write dry run payload: %w", err)
+	}
+	return nopResponse(), nil
+}
@@ -0,0 +1,134 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestWithDryRun(t *testing.T) {
+	recorder := newAlertRecorder(t)
+
+	var buf bytes.Buffer
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{},
+		WithEndpoint(recorder.URL),
+		WithBaseLabel("cluster", "prod"),
+		WithDryRun(&buf),
+	)
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	resp, err := am.Emit(NewAlert(
+		WithLabel("alertname", "Down"),
+		WithLabel("instance", "web-1"),
+		WithAnnotationTemplate("summary", "{{ $labels.instance }} is down in {{ $labels.cluster }}"),
+	))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var alerts []Alert
+	if err := json.Unmarshal(buf.Bytes(), &alerts); err != nil {
+		t.Fatalf("failed to decode payload %q: %v", buf.String(), err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Labels["cluster"] != "prod" || alerts[0].Annotations["summary"] != "web-1 is down in prod" {
+		t.Errorf("expected merged and templated alert, got %+v", alerts[0])
+	}
+	if received := recorder.received(); len(received) != 0 {
+		t.Errorf("expected no alerts to be sent, got %d", len(received))
+	}
+
+	now := time.Now()
+	invalid := NewAlert(WithLabel("alertname", "Invalid"), WithStartsAt(now), WithEndsAt(now.Add(-time.Minute)))
+	if _, err := am.Emit(invalid); !errors.Is(err, ErrInvalidAlert) {
+		t.Errorf("expected error %v, got %v", ErrInvalidAlert, err)
+	}
+
+	// without dry run, alerts are sent unvalidated and Alertmanager decides
+	am, err = NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+	resp, err = am.Emit(invalid)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	if received := recorder.received(); len(received) != 1 {
+		t.Errorf("expected invalid alert to be sent without dry run, got %d alerts", len(received))
+	}
+}
+
+func TestDryRunAPI(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(server.URL), WithDryRun(nil))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	ctx := context.Background()
+	id, err := am.CreateSilence(ctx, Silence{Matchers: []SilenceMatcher{{Name: "alertname", Value: "Test"}}})
+	if err != nil || id != "" {
+		t.Errorf("expected no silence ID and no error, got %q and %v", id, err)
+	}
+	if err := am.ExpireSilence(ctx, "1234"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := am.ListSilences(ctx); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Errorf("expected only the read-only request to be sent, got %v", methods)
+	}
+}
+
+func TestDryRunArgs(t *testing.T) {
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+
+	am, err := NewAlertmanagerWithArgs(logger, Args{
+		Enabled:         true,
+		AlertmanagerURL: "http://alertmanager.invalid:9093",
+		DryRun:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	resp, err := am.Emit(NewAlert(WithLabel("alertname", "Test")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if len(logs) != 1 || !strings.Contains(logs[0], `\"alertname\":\"Test\"`) {
+		t.Errorf("expected payload to be logged, got %v", logs)
+	}
+}
@@ -0,0 +1,98 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// Emitter sends alerts. It is implemented by Alertmanager, ReloadableAlertmanager, NopEmitter and
+// the recorder in the alertmanagertest package, so application code can depend on the interface instead of the client.
+type Emitter interface {
+	// Emit sends one or more alerts.
+	Emit(alerts ...*Alert) (*http.Response, error)
+
+	// EmitContext sends one or more alerts using the provided context.
+	EmitContext(ctx context.Context, alerts ...*Alert) (*http.Response, error)
+}
+
+var (
+	_ Emitter = (*Alertmanager)(nil)
+	_ Emitter = NopEmitter{}
+)
+
+// NopEmitter is an Emitter that discards all alerts and responds with 200 OK.
+type NopEmitter struct{}
+
+// Emit discards the alerts.
+func (NopEmitter) Emit(...*Alert) (*http.Response, error) {
+	return nopResponse(), nil
+}
+
+// EmitContext discards the alerts.
+func (NopEmitter) EmitContext(context.Context, ...*Alert) (*http.Response, error) {
+	return nopResponse(), nil
+}
+
+// nopResponse returns a synthetic 200 OK response with an empty body, so callers can
+// handle it like a response from Alertmanager.
+func nopResponse() *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+	}
+}
+
+// NewEmitterWithArgs is like NewAlertmanagerWithArgs, but returns a NopEmitter instead of nil
+// when Alertmanager is disabled, so callers never need to check for nil before emitting alerts.
+func NewEmitterWithArgs(logger logr.Logger, args Args) (Emitter, error) {
+	am, err := NewAlertmanagerWithArgs(logger, args)
+	if err != nil {
+		return nil, err
+	}
+	if am == nil {
+		return NopEmitter{}, nil
+	}
+	return am, nil
+}
+
+// emitterLogger returns the logger of e if it is an Alertmanager or ReloadableAlertmanager,
+// or a logger that discards all output.
+func emitterLogger(e Emitter) logr.Logger {
+	switch e := e.(type) {
+	case *Alertmanager:
+		if e != nil {
+			return e.log
+		}
+	case *ReloadableAlertmanager:
+		if e != nil {
+			return e.log
+		}
+	}
+	return logr.Discard()
+}
+
+// emitterMetrics returns the metrics of e if it is an Alertmanager with metrics enabled,
+// or a ReloadableAlertmanager whose current client has metrics enabled.
+func emitterMetrics(e Emitter) *Metrics {
+	switch e := e.(type) {
+	case *Alertmanager:
+		if e != nil {
+			return e.metrics
+		}
+	case *ReloadableAlertmanager:
+		if e == nil {
+			return nil
+		}
+		if am := e.Current(); am != nil {
+			return am.metrics
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestNewEmitterWithArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        Args
+		expectNop   bool
+		expectError bool
+	}{
+		{
+			name:      "disabled",
+			args:      Args{Enabled: false},
+			expectNop: true,
+		},
+		{
+			name: "enabled",
+			args: Args{Enabled: true, AlertmanagerURL: "http://alertmanager:9093"},
+		},
+		{
+			name:        "missing URL",
+			args:        Args{Enabled: true},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emitter, err := NewEmitterWithArgs(logr.Discard(), tt.args)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expected error %v, got %v", tt.expectError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			_, isNop := emitter.(NopEmitter)
+			if isNop != tt.expectNop {
+				t.Errorf("expected NopEmitter %v, got %T", tt.expectNop, emitter)
+			}
+		})
+	}
+}
+
+func TestNopEmitters(t *testing.T) {
+	var nilAlertmanager *Alertmanager
+
+	tests := []struct {
+		name    string
+		emitter Emitter
+	}{
+		{name: "NopEmitter", emitter: NopEmitter{}},
+		{name: "nil Alertmanager", emitter: nilAlertmanager},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.emitter.EmitContext(context.Background(), NewAlert(WithLabel("alertname", "Test")))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status 200, got %d", resp.StatusCode)
+			}
+
+			// helpers must accept a nil Alertmanager without panicking
+			func() {
+				defer func() { _ = recover() }()
+				defer RecoverAndAlert(context.Background(), tt.emitter)
+				panic("test")
+			}()
+		})
+	}
+}
+
+func TestEmitterHelpersTypedNil(t *testing.T) {
+	tests := []struct {
+		name    string
+		emitter Emitter
+	}{
+		{name: "nil Alertmanager", emitter: (*Alertmanager)(nil)},
+		{name: "nil ReloadableAlertmanager", emitter: (*ReloadableAlertmanager)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if logger := emitterLogger(tt.emitter); logger.GetSink() != nil {
+				t.Errorf("expected discard logger, got %v", logger)
+			}
+			if metrics := emitterMetrics(tt.emitter); metrics != nil {
+				t.Errorf("expected no metrics, got %v", metrics)
+			}
+		})
+	}
+}
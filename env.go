@@ -0,0 +1,104 @@
+package alertmanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variable names used by Args.BindEnv,
+// e.g. ALERTMANAGER_URL for the alertmanager-url flag.
+const DefaultEnvPrefix = "ALERTMANAGER_"
+
+// EnvError is returned by Args.BindEnv when an environment variable cannot be parsed.
+type EnvError struct {
+	// Name is the name of the environment variable.
+	Name string
+
+	// Value is the value of the environment variable.
+	Value string
+
+	// Err is the parse error.
+	Err error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("invalid environment variable %s=%q: %v", e.Name, e.Value, e.Err)
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// BindEnv sets Args fields from environment variables named after their flags: the flag name without
+// its alertmanager- prefix, upper-cased, with dashes replaced by underscores and prefix prepended.
+// With an empty prefix, DefaultEnvPrefix is used, so that e.g. --alertmanager-url is read from
+// ALERTMANAGER_URL and --alertmanager-tls-insecure from ALERTMANAGER_TLS_INSECURE.
+// Unset and empty variables are ignored.
+//
+// To give flags precedence over environment variables, and environment variables over defaults,
+// call BindEnv after BindFlags and before parsing the flags. All parse errors are returned at once,
+// each as an *EnvError; fields whose variables are valid are set regardless.
+func (a *Args) BindEnv(prefix string) error {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	b := &envBinder{prefix: prefix}
+	a.BindFlags(b)
+	return errors.Join(b.errs...)
+}
+
+// envBinder is a FlagBinder that sets the bound values from environment variables,
+// so that every flag bound by BindFlags can also be set from the environment.
+type envBinder struct {
+	prefix string
+	errs   []error
+}
+
+func (b *envBinder) BoolVar(p *bool, name string, _ bool, _ string) {
+	b.lookup(name, func(value string) error {
+		v, err := strconv.ParseBool(value)
+		if err == nil {
+			*p = v
+		}
+		return err
+	})
+}
+
+func (b *envBinder) StringVar(p *string, name string, _ string, _ string) {
+	b.lookup(name, func(value string) error {
+		*p = value
+		return nil
+	})
+}
+
+func (b *envBinder) DurationVar(p *time.Duration, name string, _ time.Duration, _ string) {
+	b.lookup(name, func(value string) error {
+		v, err := time.ParseDuration(value)
+		if err == nil {
+			*p = v
+		}
+		return err
+	})
+}
+
+// lookup calls set with the value of the environment variable of a flag, if set.
+func (b *envBinder) lookup(flagName string, set func(value string) error) {
+	name := envName(b.prefix, flagName)
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return
+	}
+	if err := set(value); err != nil {
+		b.errs = append(b.errs, &EnvError{Name: name, Value: value, Err: err})
+	}
+}
+
+// envName returns the name of the environment variable that Args.BindEnv reads for a flag.
+func envName(prefix, flagName string) string {
+	name := strings.TrimPrefix(flagName, "alertmanager-")
+	return prefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
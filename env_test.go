@@ -0,0 +1,123 @@
+package alertmanager
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArgsBindEnv(t *testing.T) {
+	tests := []struct {
+		name           string
+		prefix         string
+		env            map[string]string
+		flags          []string
+		expected       Args
+		expectedErrors []string
+	}{
+		{
+			name: "default prefix",
+			env: map[string]string{
+				"ALERTMANAGER_ENABLED":      "true",
+				"ALERTMANAGER_URL":          "http://alertmanager:9093",
+				"ALERTMANAGER_TIMEOUT":      "5s",
+				"ALERTMANAGER_TLS_INSECURE": "1",
+				"ALERTMANAGER_PATH_PREFIX":  "",
+			},
+			expected: Args{
+				Enabled:               true,
+				AlertmanagerURL:       "http://alertmanager:9093",
+				Timeout:               5 * time.Second,
+				TLSInsecureSkipVerify: true,
+			},
+		},
+		{
+			name:   "custom prefix",
+			prefix: "MYAPP_AM_",
+			env: map[string]string{
+				"MYAPP_AM_USERNAME":     "admin",
+				"ALERTMANAGER_URL":      "http://ignored:9093",
+				"MYAPP_AM_PROXY_URL":    "http://proxy:3128",
+				"MYAPP_AM_NO_PROXY":     "localhost",
+				"MYAPP_AM_PASSWORD":     "secret",
+				"MYAPP_AM_DRY_RUN":      "false",
+				"MYAPP_AM_URL":          "http://alertmanager:9093",
+				"MYAPP_AM_CA_CERT_PATH": "/etc/ca.pem",
+			},
+			expected: Args{
+				AlertmanagerURL: "http://alertmanager:9093",
+				Username:        "admin",
+				Password:        "secret",
+				TLSCACertPath:   "/etc/ca.pem",
+				ProxyURL:        "http://proxy:3128",
+				NoProxy:         "localhost",
+			},
+		},
+		{
+			name: "flags take precedence",
+			env: map[string]string{
+				"ALERTMANAGER_URL":     "http://env:9093",
+				"ALERTMANAGER_TIMEOUT": "5s",
+			},
+			flags: []string{"--alertmanager-url", "http://flag:9093"},
+			expected: Args{
+				AlertmanagerURL: "http://flag:9093",
+				Timeout:         5 * time.Second,
+			},
+		},
+		{
+			name: "parse errors",
+			env: map[string]string{
+				"ALERTMANAGER_ENABLED": "yes",
+				"ALERTMANAGER_TIMEOUT": "5",
+				"ALERTMANAGER_URL":     "http://alertmanager:9093",
+			},
+			expected: Args{
+				AlertmanagerURL: "http://alertmanager:9093",
+			},
+			expectedErrors: []string{
+				`invalid environment variable ALERTMANAGER_ENABLED="yes"`,
+				`invalid environment variable ALERTMANAGER_TIMEOUT="5"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			var args Args
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			args.BindFlags(fs)
+			err := args.BindEnv(tt.prefix)
+
+			if len(tt.expectedErrors) > 0 {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				var envErr *EnvError
+				if !errors.As(err, &envErr) {
+					t.Errorf("expected *EnvError, got %T", err)
+				}
+				for _, expected := range tt.expectedErrors {
+					if !strings.Contains(err.Error(), expected) {
+						t.Errorf("expected error to contain %q, got %v", expected, err)
+					}
+				}
+			} else if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if err := fs.Parse(tt.flags); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if args != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, args)
+			}
+		})
+	}
+}
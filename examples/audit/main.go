@@ -62,7 +62,7 @@ func main() {
 		alertmanager.WithLabel("audit_id", auditID),
 		alertmanager.WithEndsAt(time.Now().Add(1*time.Hour)),
 		alertmanager.WithAnnotation("summary", "ConfigMap created"),
-		alertmanager.WithAnnotation("description", fmt.Sprintf("User %s created ConfigMap %s/%s", user, resourceNamespace, resourceName)),
+		alertmanager.WithAnnotationTemplate("description", "User {{ $labels.user }} created ConfigMap {{ $labels.resource_namespace }}/{{ $labels.resource_name }}"),
 	)
 	resp, err := am.Emit(createAlert)
 	if err != nil {
@@ -88,7 +88,7 @@ func main() {
 		alertmanager.WithLabel("audit_id", auditID),
 		alertmanager.WithEndsAt(time.Now().Add(1*time.Hour)),
 		alertmanager.WithAnnotation("summary", "ConfigMap updated"),
-		alertmanager.WithAnnotation("description", fmt.Sprintf("User %s updated ConfigMap %s/%s", user, resourceNamespace, resourceName)),
+		alertmanager.WithAnnotationTemplate("description", "User {{ $labels.user }} updated ConfigMap {{ $labels.resource_namespace }}/{{ $labels.resource_name }}"),
 	)
 	resp, err = am.Emit(updateAlert)
 	if err != nil {
@@ -114,7 +114,7 @@ func main() {
 		alertmanager.WithLabel("audit_id", auditID),
 		alertmanager.WithEndsAt(time.Now().Add(1*time.Hour)),
 		alertmanager.WithAnnotation("summary", "ConfigMap updated"),
-		alertmanager.WithAnnotation("description", fmt.Sprintf("User %s updated ConfigMap %s/%s", user, resourceNamespace, resourceName)),
+		alertmanager.WithAnnotationTemplate("description", "User {{ $labels.user }} updated ConfigMap {{ $labels.resource_namespace }}/{{ $labels.resource_name }}"),
 	)
 	resp, err = am.Emit(updateAlert2)
 	if err != nil {
@@ -140,7 +140,7 @@ func main() {
 		alertmanager.WithLabel("audit_id", auditID),
 		alertmanager.WithEndsAt(time.Now().Add(1*time.Hour)),
 		alertmanager.WithAnnotation("summary", "ConfigMap deleted"),
-		alertmanager.WithAnnotation("description", fmt.Sprintf("User %s deleted ConfigMap %s/%s", user, resourceNamespace, resourceName)),
+		alertmanager.WithAnnotationTemplate("description", "User {{ $labels.user }} deleted ConfigMap {{ $labels.resource_namespace }}/{{ $labels.resource_name }}"),
 	)
 	resp, err = am.Emit(deleteAlert)
 	if err != nil {
@@ -166,7 +166,7 @@ func main() {
 		alertmanager.WithLabel("audit_id", auditID),
 		alertmanager.WithEndsAt(time.Now().Add(1*time.Hour)),
 		alertmanager.WithAnnotation("summary", "ConfigMap created"),
-		alertmanager.WithAnnotation("description", fmt.Sprintf("User %s created ConfigMap %s/%s", user, resourceNamespace, resourceName)),
+		alertmanager.WithAnnotationTemplate("description", "User {{ $labels.user }} created ConfigMap {{ $labels.resource_namespace }}/{{ $labels.resource_name }}"),
 	)
 	resp, err = am.Emit(recreateAlert)
 	if err != nil {
@@ -0,0 +1,249 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// ErrorAnnotation is the annotation holding the error of alerts created from logr Error calls.
+	ErrorAnnotation = "error"
+
+	// LoggerAnnotation is the annotation holding the name of the logger that created an alert.
+	LoggerAnnotation = "logger"
+
+	// DefaultAlertSinkQueueName is the queue label of the queue metrics of an AlertSink.
+	DefaultAlertSinkQueueName = "alert_sink"
+)
+
+// AlertSink is a logr.LogSink that delegates to another LogSink and additionally sends Error calls
+// to Alertmanager as alerts. Info calls may optionally be sent too, see WithSinkInfoAlerts.
+//
+// Only key/value pairs listed with WithSinkLabelKeys or WithSinkAnnotationKeys are sent;
+// the log message is sent as the summary annotation and the error as the error annotation.
+// Alerts are delivered asynchronously and rate limited so that logging never blocks on HTTP.
+// Call Close to deliver queued alerts before the program exits.
+type AlertSink struct {
+	delegate logr.LogSink
+	emitter  *asyncEmitter
+	cfg      *alertSinkConfig
+
+	values []any
+	names  []string
+}
+
+var (
+	_ logr.LogSink          = &AlertSink{}
+	_ logr.CallDepthLogSink = &AlertSink{}
+)
+
+type alertSinkConfig struct {
+	async          asyncConfig
+	alertName      string
+	labelKeys      []string
+	annotationKeys []string
+
+	// infoMarkerKey enables alerts for Info calls at or below infoMaxLevel that carry infoMarkerKey=true
+	infoMarkerKey string
+	infoMaxLevel  int
+}
+
+// AlertSinkOption represents a configuration option for an AlertSink.
+type AlertSinkOption func(*alertSinkConfig)
+
+// WithSinkLabelKeys sets the keys of key/value pairs sent as alert labels.
+func WithSinkLabelKeys(keys ...string) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.labelKeys = keys
+	}
+}
+
+// WithSinkAnnotationKeys sets the keys of key/value pairs sent as alert annotations.
+func WithSinkAnnotationKeys(keys ...string) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.annotationKeys = keys
+	}
+}
+
+// WithSinkAlertName sets the alertname label of alerts created from log calls.
+// Defaults to DefaultLogAlertName.
+func WithSinkAlertName(name string) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.alertName = name
+	}
+}
+
+// WithSinkInfoAlerts also sends Info calls at verbosity maxLevel or below as alerts,
+// if they carry the key/value pair markerKey=true, e.g. log.V(1).Info("msg", "alert", true).
+func WithSinkInfoAlerts(maxLevel int, markerKey string) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.infoMaxLevel = maxLevel
+		c.infoMarkerKey = markerKey
+	}
+}
+
+// WithSinkRateLimit limits the rate of alerts sent to limit per second with the given burst.
+// Alerts exceeding the rate limit are dropped. Defaults to 1 alert per second with a burst of 10.
+func WithSinkRateLimit(limit float64, burst int) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.async.limit = rate.Limit(limit)
+		c.async.burst = burst
+	}
+}
+
+// WithSinkQueueSize sets the number of alerts buffered for delivery. Alerts are dropped when the
+// queue is full. Defaults to DefaultQueueSize.
+func WithSinkQueueSize(size int) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.async.queueSize = size
+	}
+}
+
+// WithSinkQueueName sets the queue label of the queue metrics, so that several AlertSinks
+// sharing the Metrics of an Alertmanager can be told apart. Defaults to DefaultAlertSinkQueueName.
+func WithSinkQueueName(name string) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.async.queueName = name
+	}
+}
+
+// WithSinkSendTimeout sets the timeout for delivering a batch of alerts. Defaults to DefaultSendTimeout.
+func WithSinkSendTimeout(timeout time.Duration) AlertSinkOption {
+	return func(c *alertSinkConfig) {
+		c.async.sendTimeout = timeout
+	}
+}
+
+// NewAlertSink creates an AlertSink that delegates to the given LogSink and sends alerts using am.
+// Use logr.New(sink) to create a logger.
+func NewAlertSink(delegate logr.LogSink, am Emitter, options ...AlertSinkOption) *AlertSink {
+	cfg := &alertSinkConfig{
+		async:     defaultAsyncConfig(DefaultAlertSinkQueueName),
+		alertName: DefaultLogAlertName,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	return &AlertSink{
+		delegate: delegate,
+		emitter:  newAsyncEmitter(am, cfg.async),
+		cfg:      cfg,
+	}
+}
+
+// Init initializes the delegate, accounting for the call frame added by the AlertSink.
+func (s *AlertSink) Init(info logr.RuntimeInfo) {
+	info.CallDepth++
+	s.delegate.Init(info)
+}
+
+// Enabled reports whether the delegate or the AlertSink handles Info calls at the given level.
+func (s *AlertSink) Enabled(level int) bool {
+	return s.delegate.Enabled(level) || (s.cfg.infoMarkerKey != "" && level <= s.cfg.infoMaxLevel)
+}
+
+// Info logs a non-error message and sends it as an alert if Info alerts are enabled and it is marked.
+func (s *AlertSink) Info(level int, msg string, keysAndValues ...any) {
+	if s.cfg.infoMarkerKey != "" && level <= s.cfg.infoMaxLevel && s.marked(keysAndValues) {
+		s.emitter.enqueue(s.alert(nil, msg, keysAndValues))
+	}
+	if s.delegate.Enabled(level) {
+		s.delegate.Info(level, msg, keysAndValues...)
+	}
+}
+
+// Error logs an error and sends it as an alert.
+func (s *AlertSink) Error(err error, msg string, keysAndValues ...any) {
+	s.emitter.enqueue(s.alert(err, msg, keysAndValues))
+	s.delegate.Error(err, msg, keysAndValues...)
+}
+
+// WithValues returns a sink whose log calls and alerts include the given key/value pairs.
+func (s *AlertSink) WithValues(keysAndValues ...any) logr.LogSink {
+	c := *s
+	c.delegate = s.delegate.WithValues(keysAndValues...)
+	c.values = append(slices.Clip(s.values), keysAndValues...)
+	return &c
+}
+
+// WithName returns a sink with the given name appended to its logger name.
+func (s *AlertSink) WithName(name string) logr.LogSink {
+	c := *s
+	c.delegate = s.delegate.WithName(name)
+	c.names = append(slices.Clip(s.names), name)
+	return &c
+}
+
+// WithCallDepth returns a sink that skips the given number of additional call frames,
+// if the delegate supports it.
+func (s *AlertSink) WithCallDepth(depth int) logr.LogSink {
+	delegate, ok := s.delegate.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+
+	c := *s
+	c.delegate = delegate.WithCallDepth(depth)
+	return &c
+}
+
+// Close stops sending alerts and waits until all queued alerts have been delivered or ctx is done.
+// Log calls made after Close are still delegated.
+func (s *AlertSink) Close(ctx context.Context) error {
+	return s.emitter.close(ctx)
+}
+
+// marked reports whether the key/value pairs carry the Info alert marker.
+func (s *AlertSink) marked(keysAndValues []any) bool {
+	marked := false
+	s.eachKeyValue(keysAndValues, func(key string, value any) {
+		if key == s.cfg.infoMarkerKey {
+			marked = value == true
+		}
+	})
+	return marked
+}
+
+// alert converts a log call into an Alert.
+func (s *AlertSink) alert(err error, msg string, keysAndValues []any) *Alert {
+	alert := NewAlert(
+		WithLabel("alertname", s.cfg.alertName),
+		WithAnnotation(SummaryAnnotation, msg),
+	)
+	if err != nil {
+		alert.Annotations[ErrorAnnotation] = err.Error()
+	}
+	if len(s.names) > 0 {
+		alert.Annotations[LoggerAnnotation] = strings.Join(s.names, "/")
+	}
+
+	s.eachKeyValue(keysAndValues, func(key string, value any) {
+		switch {
+		case slices.Contains(s.cfg.labelKeys, key):
+			alert.Labels[key] = fmt.Sprint(value)
+		case slices.Contains(s.cfg.annotationKeys, key):
+			alert.Annotations[key] = fmt.Sprint(value)
+		}
+	})
+
+	return alert
+}
+
+// eachKeyValue calls f for every key/value pair of the sink and the log call, in that order.
+// Pairs with non-string keys and a trailing key without value are skipped.
+func (s *AlertSink) eachKeyValue(keysAndValues []any, f func(key string, value any)) {
+	for _, kvs := range [][]any{s.values, keysAndValues} {
+		for i := 0; i+1 < len(kvs); i += 2 {
+			if key, ok := kvs[i].(string); ok {
+				f(key, kvs[i+1])
+			}
+		}
+	}
+}
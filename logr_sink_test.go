@@ -0,0 +1,123 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestAlertSink(t *testing.T) {
+	tests := []struct {
+		name                string
+		options             []AlertSinkOption
+		log                 func(logger logr.Logger)
+		expectedAlerts      int
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "error with allowlisted keys",
+			options: []AlertSinkOption{
+				WithSinkLabelKeys("controller"),
+				WithSinkAnnotationKeys("object"),
+			},
+			log: func(logger logr.Logger) {
+				logger.WithName("reconciler").WithValues("controller", "pod").
+					Error(errors.New("conflict"), "failed to reconcile", "object", "default/web", "secret", "hunter2")
+			},
+			expectedAlerts: 1,
+			expectedLabels: map[string]string{
+				"alertname":  DefaultLogAlertName,
+				"controller": "pod",
+			},
+			expectedAnnotations: map[string]string{
+				SummaryAnnotation: "failed to reconcile",
+				ErrorAnnotation:   "conflict",
+				LoggerAnnotation:  "reconciler",
+				"object":          "default/web",
+			},
+		},
+		{
+			name: "info is not sent by default",
+			log: func(logger logr.Logger) {
+				logger.Info("started", "alert", true)
+			},
+		},
+		{
+			name: "marked info within verbosity",
+			options: []AlertSinkOption{
+				WithSinkInfoAlerts(1, "alert"),
+				WithSinkAlertName("Notice"),
+			},
+			log: func(logger logr.Logger) {
+				logger.V(1).Info("leader lost", "alert", true)
+				logger.V(2).Info("too verbose", "alert", true)
+				logger.Info("unmarked")
+			},
+			expectedAlerts: 1,
+			expectedLabels: map[string]string{
+				"alertname": "Notice",
+			},
+			expectedAnnotations: map[string]string{
+				SummaryAnnotation: "leader lost",
+			},
+		},
+		{
+			name: "rate limited",
+			options: []AlertSinkOption{
+				WithSinkRateLimit(0, 1),
+			},
+			log: func(logger logr.Logger) {
+				for range 3 {
+					logger.Error(nil, "flapping")
+				}
+			},
+			expectedAlerts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newAlertRecorder(t)
+			am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			var lines []string
+			delegate := funcr.New(func(prefix, args string) {
+				lines = append(lines, args)
+			}, funcr.Options{})
+
+			sink := NewAlertSink(delegate.GetSink(), am, tt.options...)
+			tt.log(logr.New(sink))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := sink.Close(ctx); err != nil {
+				t.Fatalf("failed to close sink: %v", err)
+			}
+
+			if len(lines) == 0 {
+				t.Errorf("expected log calls to be delegated")
+			}
+
+			alerts := recorder.received()
+			if len(alerts) != tt.expectedAlerts {
+				t.Fatalf("expected %d alerts, got %d", tt.expectedAlerts, len(alerts))
+			}
+			if tt.expectedLabels != nil && !maps.Equal(alerts[0].Labels, tt.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tt.expectedLabels, alerts[0].Labels)
+			}
+			if tt.expectedAnnotations != nil && !maps.Equal(alerts[0].Annotations, tt.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tt.expectedAnnotations, alerts[0].Annotations)
+			}
+		})
+	}
+}
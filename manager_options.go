@@ -1,14 +1,20 @@
 package alertmanager
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // TLSVersion represents a TLS protocol version.
@@ -27,7 +33,23 @@ const (
 // ManagerOption represents a configuration option for Alertmanager.
 type ManagerOption func(*Alertmanager) error
 
+// WithSlogLogger logs through the provided slog.Logger instead of the logr.Logger passed to
+// NewAlertmanager. Debug messages (logr verbosity 1) are logged at slog.Level(-1),
+// so they are visible when the handler level is slog.LevelDebug.
+// Both loggers receive the same structured keys: endpoint, alert_count, status and payload.
+func WithSlogLogger(logger *slog.Logger) ManagerOption {
+	return func(a *Alertmanager) error {
+		if logger == nil {
+			return ErrNilLogger
+		}
+		a.log = logr.FromSlogHandler(logger.Handler())
+		return nil
+	}
+}
+
 // WithEndpoint sets the Alertmanager endpoint URL.
+// Any path in the URL is stripped; use WithPathPrefix when Alertmanager is served
+// under a route prefix or behind a reverse proxy subpath.
 func WithEndpoint(endpoint string) ManagerOption {
 	return func(a *Alertmanager) error {
 		if endpoint == "" {
@@ -36,17 +58,35 @@ func WithEndpoint(endpoint string) ManagerOption {
 
 		u, err := url.Parse(endpoint)
 		if err != nil {
-			return errors.Wrap(err, "invalid Alertmanager config: failed to parse endpoint")
+			// the url.Error is not wrapped, since it contains the endpoint with its credentials
+			return errors.Wrapf(ErrInvalidEndpoint, "failed to parse endpoint %q", redactURL(endpoint))
 		}
 		if u.Scheme == "" || u.Host == "" {
 			return ErrInvalidEndpoint
 		}
 		if u.Path != "" {
-			a.log.V(1).Info("stripping path from Alertmanager endpoint", "path", u.Path)
+			a.log.V(1).Info("stripping path from Alertmanager endpoint", logKeyEndpoint, redactURL(endpoint), "path", u.Path)
 			u.Path = ""
 		}
 
-		a.endpoint = fmt.Sprintf("%s/api/v2/alerts", u.String())
+		a.baseURL = u.String()
+		a.setEndpoint()
+		return nil
+	}
+}
+
+// WithPathPrefix sets a path prefix that is prepended to all Alertmanager API paths,
+// e.g. the value of Alertmanager's --web.route-prefix, an ingress subpath,
+// or MimirPathPrefix for the Mimir and Cortex Alertmanager.
+// The prefix is applied regardless of whether it is set before or after WithEndpoint.
+func WithPathPrefix(prefix string) ManagerOption {
+	return func(a *Alertmanager) error {
+		prefix = strings.TrimRight(prefix, "/")
+		if prefix != "" && !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+		a.pathPrefix = prefix
+		a.setEndpoint()
 		return nil
 	}
 }
@@ -55,6 +95,102 @@ func WithEndpoint(endpoint string) ManagerOption {
 func WithBasicAuth(username, password string) ManagerOption {
 	return func(a *Alertmanager) error {
 		a.authHeader = basicAuthHeader(username, password)
+		a.authFile = nil
+		return nil
+	}
+}
+
+// WithAuthorization sets the Authorization header to credentialsType followed by credentials,
+// e.g. a bearer token. credentialsType defaults to "Bearer".
+func WithAuthorization(credentialsType, credentials string) ManagerOption {
+	return func(a *Alertmanager) error {
+		if credentialsType == "" {
+			credentialsType = "Bearer"
+		}
+		a.authHeader = credentialsType + " " + credentials
+		a.authFile = nil
+		return nil
+	}
+}
+
+// WithBasicAuthFile sets basic authentication credentials with a password read from a file.
+// The file is read when the client is created, and read again whenever it changes.
+func WithBasicAuthFile(username, passwordFile string) ManagerOption {
+	return func(a *Alertmanager) error {
+		file, err := newSecretFile(passwordFile)
+		if err != nil {
+			return errors.Wrap(err, "invalid Alertmanager config: failed to read password file")
+		}
+		a.authHeader = ""
+		a.authFile = file
+		a.authFormat = func(password string) string {
+			return basicAuthHeader(username, password)
+		}
+		return nil
+	}
+}
+
+// WithAuthorizationFile is like WithAuthorization, but reads the credentials from a file,
+// e.g. a bearer token file. The file is read when the client is created, and read again whenever it changes.
+func WithAuthorizationFile(credentialsType, credentialsFile string) ManagerOption {
+	return func(a *Alertmanager) error {
+		file, err := newSecretFile(credentialsFile)
+		if err != nil {
+			return errors.Wrap(err, "invalid Alertmanager config: failed to read credentials file")
+		}
+		if credentialsType == "" {
+			credentialsType = "Bearer"
+		}
+		a.authHeader = ""
+		a.authFile = file
+		a.authFormat = func(credentials string) string {
+			return credentialsType + " " + credentials
+		}
+		return nil
+	}
+}
+
+// WithClientCertificate configures TLS client authentication with a PEM-encoded certificate and key.
+func WithClientCertificate(certPEM, keyPEM []byte) ManagerOption {
+	return func(a *Alertmanager) error {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return errors.Wrap(err, "invalid Alertmanager config: failed to load client certificate")
+		}
+
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
+		}
+
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			}
+		}
+
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+		return nil
+	}
+}
+
+// WithServerName sets the server name used to verify the certificate of Alertmanager.
+func WithServerName(serverName string) ManagerOption {
+	return func(a *Alertmanager) error {
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
+		}
+
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			}
+		}
+
+		transport.TLSClientConfig.ServerName = serverName
+
 		return nil
 	}
 }
@@ -71,9 +207,9 @@ func WithCustomCA(caCert []byte) ManagerOption {
 			caCertPool.AppendCertsFromPEM(caCert)
 		}
 
-		transport, ok := a.client.Transport.(*http.Transport)
-		if !ok {
-			transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
 		}
 
 		if transport.TLSClientConfig == nil {
@@ -83,7 +219,6 @@ func WithCustomCA(caCert []byte) ManagerOption {
 		}
 
 		transport.TLSClientConfig.RootCAs = caCertPool
-		a.client.Transport = transport
 
 		return nil
 	}
@@ -92,9 +227,9 @@ func WithCustomCA(caCert []byte) ManagerOption {
 // WithInsecure configures TLS to skip certificate verification.
 func WithInsecure(insecureSkipVerify bool) ManagerOption {
 	return func(a *Alertmanager) error {
-		transport, ok := a.client.Transport.(*http.Transport)
-		if !ok {
-			transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
 		}
 
 		if transport.TLSClientConfig == nil {
@@ -104,7 +239,6 @@ func WithInsecure(insecureSkipVerify bool) ManagerOption {
 		}
 
 		transport.TLSClientConfig.InsecureSkipVerify = insecureSkipVerify
-		a.client.Transport = transport
 
 		return nil
 	}
@@ -115,9 +249,9 @@ func WithInsecure(insecureSkipVerify bool) ManagerOption {
 // If not specified, TLS 1.2 is used as the default minimum.
 func WithMinTLSVersion(minVersion TLSVersion) ManagerOption {
 	return func(a *Alertmanager) error {
-		transport, ok := a.client.Transport.(*http.Transport)
-		if !ok {
-			transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
 		}
 
 		if transport.TLSClientConfig == nil {
@@ -125,7 +259,6 @@ func WithMinTLSVersion(minVersion TLSVersion) ManagerOption {
 		}
 
 		transport.TLSClientConfig.MinVersion = uint16(minVersion)
-		a.client.Transport = transport
 
 		return nil
 	}
@@ -135,9 +268,9 @@ func WithMinTLSVersion(minVersion TLSVersion) ManagerOption {
 // Use the TLS* constants (e.g., TLS12, TLS13).
 func WithMaxTLSVersion(maxVersion TLSVersion) ManagerOption {
 	return func(a *Alertmanager) error {
-		transport, ok := a.client.Transport.(*http.Transport)
-		if !ok {
-			transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
 		}
 
 		if transport.TLSClientConfig == nil {
@@ -147,12 +280,110 @@ func WithMaxTLSVersion(maxVersion TLSVersion) ManagerOption {
 		}
 
 		transport.TLSClientConfig.MaxVersion = uint16(maxVersion)
-		a.client.Transport = transport
 
 		return nil
 	}
 }
 
+// WithProxyURL routes all requests through the given HTTP or HTTPS proxy.
+// Credentials in the proxy URL are sent to the proxy using basic authentication.
+// WithProxyURL and WithProxyFromEnvironment are mutually exclusive.
+func WithProxyURL(proxyURL string) ManagerOption {
+	return func(a *Alertmanager) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			// the url.Error is not wrapped, since it contains the proxy URL with its credentials
+			return errors.Wrapf(ErrInvalidProxyURL, "failed to parse proxy URL %q", redactURL(proxyURL))
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return ErrInvalidProxyURL
+		}
+
+		a.proxyURL = u
+		return a.applyProxy()
+	}
+}
+
+// WithNoProxy sets a comma-separated list of hosts, domains, IP addresses and CIDR ranges
+// that are reached directly rather than through the proxy set by WithProxyURL.
+// As with the NO_PROXY environment variable, localhost and loopback addresses are then always reached directly.
+// It has no effect without WithProxyURL.
+func WithNoProxy(noProxy string) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.noProxy = noProxy
+		return a.applyProxy()
+	}
+}
+
+// WithProxyFromEnvironment uses the proxy configured by the HTTP_PROXY, HTTPS_PROXY
+// and NO_PROXY environment variables (or their lowercase versions).
+func WithProxyFromEnvironment() ManagerOption {
+	return func(a *Alertmanager) error {
+		a.proxyFromEnvironment = true
+		return a.applyProxy()
+	}
+}
+
+// WithProxyConnectHeader sets headers that are sent to the proxy during CONNECT requests,
+// which are used to tunnel HTTPS connections.
+func WithProxyConnectHeader(header http.Header) ManagerOption {
+	return func(a *Alertmanager) error {
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
+		}
+		transport.ProxyConnectHeader = header.Clone()
+		return nil
+	}
+}
+
+// WithDialContext sets the function used to establish connections to Alertmanager,
+// e.g. to connect through a custom network or a sidecar.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ManagerOption {
+	return func(a *Alertmanager) error {
+		if dial == nil {
+			return ErrNilDialer
+		}
+
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
+		}
+		transport.DialContext = dial
+		return nil
+	}
+}
+
+// WithUnixSocket connects to Alertmanager over the Unix domain socket at the given path.
+// The host of the endpoint is only used for the Host header; if no endpoint is set,
+// http://localhost is used. Proxies from the environment are not used unless
+// WithProxyURL or WithProxyFromEnvironment is set.
+func WithUnixSocket(path string) ManagerOption {
+	return func(a *Alertmanager) error {
+		if path == "" {
+			return ErrUnixSocketRequired
+		}
+
+		dialer := &net.Dialer{}
+		transport, err := a.baseTransport()
+		if err != nil {
+			return err
+		}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		}
+		if a.proxyURL == nil && !a.proxyFromEnvironment {
+			transport.Proxy = nil
+		}
+
+		if a.baseURL == "" {
+			a.baseURL = "http://localhost"
+			a.setEndpoint()
+		}
+		return nil
+	}
+}
+
 // WithTimeout sets the HTTP client timeout on the existing client.
 func WithTimeout(timeout time.Duration) ManagerOption {
 	return func(a *Alertmanager) error {
@@ -182,3 +413,148 @@ func WithBaseAnnotation(key, value string) ManagerOption {
 		return nil
 	}
 }
+
+// WithRoundTripper layers middleware on top of the HTTP transport, e.g. for tracing or logging.
+// TLS, proxy and dialer options configure the base transport beneath all middleware.
+// Middleware is applied in order, so the last WithRoundTripper is the outermost.
+func WithRoundTripper(middleware func(http.RoundTripper) http.RoundTripper) ManagerOption {
+	return func(a *Alertmanager) error {
+		if middleware == nil {
+			return ErrNilRoundTripper
+		}
+		a.middleware = append(a.middleware, middleware)
+		return nil
+	}
+}
+
+// baseTransport returns the base transport configured by TLS, proxy and dialer options.
+// It is a clone of the HTTP client's *http.Transport, or of http.DefaultTransport otherwise,
+// so the caller's transport is never modified. Returns ErrUnsupportedTransport if the HTTP client
+// has a transport of another type, since its settings cannot be changed.
+func (a *Alertmanager) baseTransport() (*http.Transport, error) {
+	if a.transport != nil {
+		return a.transport, nil
+	}
+
+	switch transport := a.client.Transport.(type) {
+	case nil:
+		a.transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		a.transport = transport.Clone()
+	default:
+		return nil, ErrUnsupportedTransport
+	}
+
+	return a.transport, nil
+}
+
+// buildTransport sets the transport of the Alertmanager's copy of the HTTP client to the base
+// transport wrapped in all middleware.
+func (a *Alertmanager) buildTransport() {
+	rt := a.client.Transport
+	if a.transport != nil {
+		rt = a.transport
+	}
+
+	if len(a.middleware) > 0 && rt == nil {
+		rt = http.DefaultTransport
+	}
+	for _, middleware := range a.middleware {
+		rt = middleware(rt)
+	}
+
+	a.client.Transport = rt
+}
+
+// applyProxy configures the proxy of the HTTP transport from the proxy settings.
+func (a *Alertmanager) applyProxy() error {
+	if a.proxyURL != nil && a.proxyFromEnvironment {
+		return ErrConflictingProxy
+	}
+
+	transport, err := a.baseTransport()
+	if err != nil {
+		return err
+	}
+	switch {
+	case a.proxyFromEnvironment:
+		transport.Proxy = http.ProxyFromEnvironment
+	case a.proxyURL != nil && a.noProxy == "":
+		transport.Proxy = http.ProxyURL(a.proxyURL)
+	case a.proxyURL != nil:
+		// unlike http.ProxyURL, httpproxy never proxies requests to localhost and loopback addresses
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  a.proxyURL.String(),
+			HTTPSProxy: a.proxyURL.String(),
+			NoProxy:    a.noProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	return nil
+}
+
+// deferredLogSink is a logr.LogSink that records messages logged while options are applied,
+// so that they can be replayed to the logger selected by the options.
+type deferredLogSink struct {
+	entries *[]deferredLog
+	names   []string
+	values  []any
+}
+
+type deferredLog struct {
+	names  []string
+	values []any
+	level  int
+	err    error
+	msg    string
+	kv     []any
+	isErr  bool
+}
+
+var _ logr.LogSink = &deferredLogSink{}
+
+func (s *deferredLogSink) Init(logr.RuntimeInfo) {}
+
+func (s *deferredLogSink) Enabled(int) bool {
+	return true
+}
+
+func (s *deferredLogSink) Info(level int, msg string, keysAndValues ...any) {
+	*s.entries = append(*s.entries, deferredLog{names: s.names, values: s.values, level: level, msg: msg, kv: keysAndValues})
+}
+
+func (s *deferredLogSink) Error(err error, msg string, keysAndValues ...any) {
+	*s.entries = append(*s.entries, deferredLog{names: s.names, values: s.values, err: err, msg: msg, kv: keysAndValues, isErr: true})
+}
+
+func (s *deferredLogSink) WithValues(keysAndValues ...any) logr.LogSink {
+	c := *s
+	c.values = append(slices.Clip(s.values), keysAndValues...)
+	return &c
+}
+
+func (s *deferredLogSink) WithName(name string) logr.LogSink {
+	c := *s
+	c.names = append(slices.Clip(s.names), name)
+	return &c
+}
+
+// replay logs all recorded messages to logger.
+func (s *deferredLogSink) replay(logger logr.Logger) {
+	for _, entry := range *s.entries {
+		l := logger
+		for _, name := range entry.names {
+			l = l.WithName(name)
+		}
+		l = l.WithValues(entry.values...)
+		if entry.isErr {
+			l.Error(entry.err, entry.msg, entry.kv...)
+		} else {
+			l.V(entry.level).Info(entry.msg, entry.kv...)
+		}
+	}
+	*s.entries = nil
+}
@@ -0,0 +1,179 @@
+package alertmanager
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultMetricsNamespace is the namespace of the metrics created by NewMetrics.
+const DefaultMetricsNamespace = "alertmanager_client"
+
+const (
+	metricsSubsystem  = "notifications"
+	alertmanagerLabel = "alertmanager"
+	codeLabel         = "code"
+	queueLabel        = "queue"
+
+	// codeError is the code label value used when no response was received from Alertmanager.
+	codeError = "error"
+)
+
+// Metrics holds Prometheus metrics for the alerts sent by one or more Alertmanager clients.
+// Metric names follow the prometheus_notifications_* metrics of the Prometheus notifier,
+// e.g. alertmanager_client_notifications_sent_total.
+type Metrics struct {
+	sent          *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+	dropped       prometheus.Counter
+	latency       *prometheus.HistogramVec
+	batchSize     prometheus.Histogram
+	queueLength   *prometheus.GaugeVec
+	queueCapacity *prometheus.GaugeVec
+}
+
+// MetricsOption represents a configuration option for Metrics.
+type MetricsOption func(*metricsConfig)
+
+type metricsConfig struct {
+	namespace      string
+	constLabels    prometheus.Labels
+	latencyBuckets []float64
+}
+
+// WithMetricsNamespace sets the namespace of all metrics. If not specified, DefaultMetricsNamespace is used.
+func WithMetricsNamespace(namespace string) MetricsOption {
+	return func(c *metricsConfig) {
+		c.namespace = namespace
+	}
+}
+
+// WithMetricsConstLabels sets labels that are added to all metrics, e.g. to distinguish clients.
+func WithMetricsConstLabels(labels map[string]string) MetricsOption {
+	return func(c *metricsConfig) {
+		c.constLabels = labels
+	}
+}
+
+// WithLatencyBuckets sets the buckets of the request latency histogram.
+// If not specified, prometheus.DefBuckets is used.
+func WithLatencyBuckets(buckets []float64) MetricsOption {
+	return func(c *metricsConfig) {
+		c.latencyBuckets = buckets
+	}
+}
+
+// NewMetrics creates Metrics and registers them with the provided Registerer.
+// Use WithMetrics to record metrics for an Alertmanager client.
+func NewMetrics(reg prometheus.Registerer, options ...MetricsOption) (*Metrics, error) {
+	cfg := &metricsConfig{
+		namespace:      DefaultMetricsNamespace,
+		latencyBuckets: prometheus.DefBuckets,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	m := &Metrics{
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "sent_total",
+			Help:        "Total number of alerts sent, including alerts that failed to send.",
+			ConstLabels: cfg.constLabels,
+		}, []string{alertmanagerLabel}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "errors_total",
+			Help:        "Total number of sent alerts affected by errors, by response status code.",
+			ConstLabels: cfg.constLabels,
+		}, []string{alertmanagerLabel, codeLabel}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "dropped_total",
+			Help:        "Total number of alerts dropped before they could be sent.",
+			ConstLabels: cfg.constLabels,
+		}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "latency_seconds",
+			Help:        "Latency of requests to Alertmanager.",
+			Buckets:     cfg.latencyBuckets,
+			ConstLabels: cfg.constLabels,
+		}, []string{alertmanagerLabel}),
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "batch_size",
+			Help:        "Number of alerts sent per request to Alertmanager.",
+			Buckets:     []float64{1, 2, 5, 10, 20, 50, 100, 200},
+			ConstLabels: cfg.constLabels,
+		}),
+		queueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "queue_length",
+			Help:        "Number of alerts queued for sending, by queue.",
+			ConstLabels: cfg.constLabels,
+		}, []string{queueLabel}),
+		queueCapacity: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "queue_capacity",
+			Help:        "Capacity of the queue of alerts to be sent, by queue.",
+			ConstLabels: cfg.constLabels,
+		}, []string{queueLabel}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.sent, m.errors, m.dropped, m.latency, m.batchSize, m.queueLength, m.queueCapacity,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// AddDropped records alerts that were dropped before they could be sent,
+// e.g. because a queue of pending alerts was full.
+func (m *Metrics) AddDropped(n int) {
+	m.dropped.Add(float64(n))
+}
+
+// SetQueue records the length and capacity of the named queue of alerts waiting to be sent.
+// Queues sharing the same Metrics must have distinct names, since each name is a separate series.
+func (m *Metrics) SetQueue(queue string, length, capacity int) {
+	m.queueLength.WithLabelValues(queue).Set(float64(length))
+	m.queueCapacity.WithLabelValues(queue).Set(float64(capacity))
+}
+
+// observeRequest records a request that sent n alerts to the Alertmanager at the given URL,
+// which must not contain credentials and should not vary per request or tenant.
+// statusCode is zero if no response was received.
+func (m *Metrics) observeRequest(endpoint string, n int, statusCode int, duration time.Duration) {
+	m.sent.WithLabelValues(endpoint).Add(float64(n))
+	m.batchSize.Observe(float64(n))
+	m.latency.WithLabelValues(endpoint).Observe(duration.Seconds())
+
+	switch {
+	case statusCode == 0:
+		m.errors.WithLabelValues(endpoint, codeError).Add(float64(n))
+	case statusCode/100 != 2:
+		m.errors.WithLabelValues(endpoint, strconv.Itoa(statusCode)).Add(float64(n))
+	}
+}
+
+// WithMetrics records Prometheus metrics for all alerts sent by the Alertmanager.
+// The same Metrics may be shared by multiple Alertmanager clients.
+func WithMetrics(metrics *Metrics) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.metrics = metrics
+		return nil
+	}
+}
@@ -0,0 +1,224 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	logger := logr.Discard()
+
+	tests := []struct {
+		name           string
+		serverStatus   int // 0 = no server
+		alerts         []*Alert
+		expectedSent   float64
+		expectedErrors float64
+		expectedCode   string
+	}{
+		{
+			name:         "successful emit",
+			serverStatus: http.StatusOK,
+			alerts: []*Alert{
+				NewAlert(WithLabel("alertname", "a")),
+				NewAlert(WithLabel("alertname", "b")),
+			},
+			expectedSent: 2,
+		},
+		{
+			name:           "server error",
+			serverStatus:   http.StatusInternalServerError,
+			alerts:         []*Alert{NewAlert(WithLabel("alertname", "a"))},
+			expectedSent:   1,
+			expectedErrors: 1,
+			expectedCode:   "500",
+		},
+		{
+			name:           "connection error",
+			alerts:         []*Alert{NewAlert(WithLabel("alertname", "a"))},
+			expectedSent:   1,
+			expectedErrors: 1,
+			expectedCode:   codeError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.serverStatus)
+			}))
+			endpoint := server.URL
+			if tt.serverStatus == 0 {
+				server.Close()
+			} else {
+				defer server.Close()
+			}
+
+			reg := prometheus.NewPedanticRegistry()
+			metrics, err := NewMetrics(reg)
+			if err != nil {
+				t.Fatalf("failed to create metrics: %v", err)
+			}
+
+			am, err := NewAlertmanager(logger, &http.Client{}, WithEndpoint(endpoint), WithMetrics(metrics))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			resp, _ := am.Emit(tt.alerts...)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if got := testutil.ToFloat64(metrics.sent.WithLabelValues(am.displayURL)); got != tt.expectedSent {
+				t.Errorf("expected %v alerts sent, got %v", tt.expectedSent, got)
+			}
+			if tt.expectedCode != "" {
+				if got := testutil.ToFloat64(metrics.errors.WithLabelValues(am.displayURL, tt.expectedCode)); got != tt.expectedErrors {
+					t.Errorf("expected %v alert errors, got %v", tt.expectedErrors, got)
+				}
+			} else if got := testutil.CollectAndCount(metrics.errors); got != 0 {
+				t.Errorf("expected no alert errors, got %d series", got)
+			}
+			if got := testutil.CollectAndCount(metrics.latency); got != 1 {
+				t.Errorf("expected 1 latency series, got %d", got)
+			}
+		})
+	}
+}
+
+func TestMetricsAlertmanagerLabel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics, err := NewMetrics(prometheus.NewPedanticRegistry())
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	endpoint := strings.Replace(server.URL, "http://", "http://admin:s3cret@", 1)
+	tc, err := NewTenantClient(logr.Discard(), &http.Client{},
+		WithEndpoint(endpoint), WithPathPrefix("/alertmanager"), WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("failed to create tenant client: %v", err)
+	}
+
+	for _, tenant := range []string{"team-a", "team-b"} {
+		resp, err := tc.EmitForTenant(context.Background(), tenant, NewAlert(WithLabel("alertname", "a")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	expected := server.URL + "/alertmanager"
+	if got := testutil.ToFloat64(metrics.sent.WithLabelValues(expected)); got != 2 {
+		t.Errorf("expected 2 alerts sent to %s, got %v", expected, got)
+	}
+	if got := testutil.CollectAndCount(metrics.sent); got != 1 {
+		t.Errorf("expected 1 series for all tenants, got %d", got)
+	}
+}
+
+func TestNewMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	metrics, err := NewMetrics(reg, WithMetricsNamespace("test"), WithMetricsConstLabels(map[string]string{"client": "a"}))
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	metrics.AddDropped(3)
+	metrics.SetQueue("a", 5, 10)
+	metrics.SetQueue("b", 1, 10)
+
+	if got := testutil.ToFloat64(metrics.dropped); got != 3 {
+		t.Errorf("expected 3 dropped alerts, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.queueLength.WithLabelValues("a")); got != 5 {
+		t.Errorf("expected queue length 5, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.queueLength); got != 2 {
+		t.Errorf("expected a queue length series per queue, got %d", got)
+	}
+
+	if _, err := NewMetrics(reg, WithMetricsNamespace("test"), WithMetricsConstLabels(map[string]string{"client": "a"})); err == nil {
+		t.Errorf("expected duplicate registration to fail")
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "test_notifications_dropped_total" {
+			return
+		}
+	}
+	t.Errorf("expected test_notifications_dropped_total to be registered")
+}
+
+func TestQueueMetrics(t *testing.T) {
+	recorder := newAlertRecorder(t)
+
+	newClient := func() (*Alertmanager, *Metrics) {
+		metrics, err := NewMetrics(prometheus.NewRegistry())
+		if err != nil {
+			t.Fatalf("failed to create metrics: %v", err)
+		}
+		am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL), WithMetrics(metrics))
+		if err != nil {
+			t.Fatalf("failed to create alertmanager: %v", err)
+		}
+		return am, metrics
+	}
+
+	first, firstMetrics := newClient()
+	current := first
+	reloadable, err := NewReloadableAlertmanager(logr.Discard(), func() (*Alertmanager, error) {
+		return current, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to create reloadable alertmanager: %v", err)
+	}
+
+	handler := NewAlertHandler(slog.DiscardHandler, reloadable, WithAlertQueueSize(5))
+	sink := NewAlertSink(funcr.New(func(string, string) {}, funcr.Options{}).GetSink(), reloadable, WithSinkQueueSize(7))
+	defer sink.Close(context.Background())
+
+	slog.New(handler).Error("failed", "alert", true)
+	if err := handler.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close handler: %v", err)
+	}
+	if got := testutil.ToFloat64(firstMetrics.queueCapacity.WithLabelValues(DefaultAlertHandlerQueueName)); got != 5 {
+		t.Errorf("expected capacity 5 of the handler queue, got %v", got)
+	}
+
+	second, secondMetrics := newClient()
+	current = second
+	if err := reloadable.Reload(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+
+	logr.New(sink).Error(errors.New("boom"), "failed")
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close sink: %v", err)
+	}
+	if got := testutil.ToFloat64(secondMetrics.queueCapacity.WithLabelValues(DefaultAlertSinkQueueName)); got != 7 {
+		t.Errorf("expected queue metrics of the reloaded client, got capacity %v", got)
+	}
+	if got := testutil.CollectAndCount(firstMetrics.queueCapacity); got != 1 {
+		t.Errorf("expected no queue metrics of the sink on the replaced client, got %d series", got)
+	}
+}
@@ -0,0 +1,400 @@
+package alertmanager
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// HighErrorRateAlertName is the alertname label of alerts for routes with a high 5xx ratio.
+	HighErrorRateAlertName = "HighErrorRate"
+
+	// HighLatencyAlertName is the alertname label of alerts for routes with too many slow requests.
+	HighLatencyAlertName = "HighLatency"
+
+	// UnmatchedRoute is the route label of requests for which no route could be determined.
+	UnmatchedRoute = "unmatched"
+
+	// DefaultRouteMonitorQueueName is the queue label of the queue metrics of a RouteMonitor.
+	DefaultRouteMonitorQueueName = "route_monitor"
+
+	// windowBuckets is the number of buckets of the sliding window of each route.
+	windowBuckets = 10
+
+	// minWindow is the shortest sliding window, so that buckets and the evaluation interval are never empty.
+	minWindow = time.Second
+)
+
+// RouteMonitor is HTTP middleware that keeps sliding-window statistics per route and method,
+// fires an alert when a route's 5xx ratio or ratio of slow requests crosses a threshold,
+// and resolves it when the route recovers. Alerts are labeled with route and method
+// and delivered asynchronously, so requests never block on Alertmanager.
+type RouteMonitor struct {
+	cfg     *routeMonitorConfig
+	emitter *asyncEmitter
+
+	mu     sync.Mutex
+	routes map[routeKey]*routeStats
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+type routeMonitorConfig struct {
+	async          asyncConfig
+	window         time.Duration
+	minRequests    int
+	errorRatio     float64
+	latency        time.Duration
+	slowRatio      float64
+	resendInterval time.Duration
+	routeFunc      func(*http.Request) string
+	alertOptions   []AlertOption
+	now            func() time.Time
+}
+
+// RouteMonitorOption represents a configuration option for a RouteMonitor.
+type RouteMonitorOption func(*routeMonitorConfig)
+
+// WithWindow sets the duration of the sliding window over which statistics are computed.
+// Defaults to 5 minutes. Windows shorter than 1 second are raised to 1 second.
+func WithWindow(window time.Duration) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.window = window
+	}
+}
+
+// WithMinRequests sets the minimum number of requests in the window before a route is evaluated.
+// Routes with fewer requests are considered healthy. Defaults to 20.
+func WithMinRequests(n int) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.minRequests = n
+	}
+}
+
+// WithErrorRatioThreshold sets the ratio of 5xx responses above which a route fires a
+// HighErrorRate alert. Defaults to 0.05. A ratio of zero or less disables error rate alerts.
+func WithErrorRatioThreshold(ratio float64) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.errorRatio = ratio
+	}
+}
+
+// WithLatencyThreshold fires a HighLatency alert when the ratio of requests slower than latency
+// exceeds slowRatio. Latency alerts are disabled by default.
+func WithLatencyThreshold(latency time.Duration, slowRatio float64) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.latency = latency
+		c.slowRatio = slowRatio
+	}
+}
+
+// WithResendInterval sets how often alerts are re-sent while a route remains unhealthy,
+// so that Alertmanager does not resolve them. Defaults to 1 minute.
+func WithResendInterval(interval time.Duration) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.resendInterval = interval
+	}
+}
+
+// WithRouteFunc sets the function that determines the route label of a request.
+// It is called after the request was served. Defaults to the pattern matched by http.ServeMux,
+// or UnmatchedRoute. Avoid using raw URL paths, since every distinct route is tracked separately.
+func WithRouteFunc(f func(*http.Request) string) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.routeFunc = f
+	}
+}
+
+// WithRouteAlertOptions sets options applied to all route alerts, e.g. to add service labels.
+func WithRouteAlertOptions(options ...AlertOption) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.alertOptions = append(c.alertOptions, options...)
+	}
+}
+
+// WithRouteQueueName sets the queue label of the queue metrics, so that several RouteMonitors
+// sharing the Metrics of an Alertmanager can be told apart. Defaults to DefaultRouteMonitorQueueName.
+func WithRouteQueueName(name string) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.async.queueName = name
+	}
+}
+
+// NewRouteMonitor creates a RouteMonitor that sends alerts using am.
+// Call Close to stop evaluating routes and deliver queued alerts.
+func NewRouteMonitor(am Emitter, options ...RouteMonitorOption) *RouteMonitor {
+	cfg := &routeMonitorConfig{
+		async:          defaultAsyncConfig(DefaultRouteMonitorQueueName),
+		window:         5 * time.Minute,
+		minRequests:    20,
+		errorRatio:     0.05,
+		resendInterval: time.Minute,
+		routeFunc:      defaultRoute,
+		now:            time.Now,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	switch {
+	case cfg.window <= 0:
+		cfg.window = 5 * time.Minute
+	case cfg.window < minWindow:
+		cfg.window = minWindow
+	}
+
+	// firing and resolved notifications must never be dropped by the rate limiter
+	cfg.async.limit = rate.Inf
+
+	m := &RouteMonitor{
+		cfg:     cfg,
+		emitter: newAsyncEmitter(am, cfg.async),
+		routes:  make(map[routeKey]*routeStats),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go m.run()
+
+	return m
+}
+
+// Middleware returns an http.Handler that records the outcome of every request served by next.
+func (m *RouteMonitor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := m.cfg.now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		// a panicking handler is recorded as a 5xx response before the panic propagates
+		completed := false
+		defer func() {
+			key := routeKey{route: m.cfg.routeFunc(r), method: r.Method}
+			slow := m.cfg.latency > 0 && m.cfg.now().Sub(start) > m.cfg.latency
+			m.observe(key, !completed || sw.status >= 500, slow)
+		}()
+
+		next.ServeHTTP(sw, r)
+		completed = true
+	})
+}
+
+// Close stops evaluating routes and waits until all queued alerts have been delivered or ctx is done.
+func (m *RouteMonitor) Close(ctx context.Context) error {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	<-m.done
+	return m.emitter.close(ctx)
+}
+
+// run periodically evaluates all routes so that alerts resolve even when traffic stops.
+func (m *RouteMonitor) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.window / windowBuckets)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.evaluateAll()
+		}
+	}
+}
+
+func (m *RouteMonitor) observe(key routeKey, failed, slow bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.routes[key]
+	if !ok {
+		stats = &routeStats{window: newSlidingWindow(m.cfg.window)}
+		m.routes[key] = stats
+	}
+
+	now := m.cfg.now()
+	stats.window.add(now, failed, slow)
+	m.evaluate(key, stats, now)
+}
+
+func (m *RouteMonitor) evaluateAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.cfg.now()
+	for key, stats := range m.routes {
+		m.evaluate(key, stats, now)
+	}
+}
+
+// evaluate updates the alert states of a route. Must be called with m.mu held.
+func (m *RouteMonitor) evaluate(key routeKey, stats *routeStats, now time.Time) {
+	total, failed, slow := stats.window.sum(now)
+	enough := total > 0 && total >= m.cfg.minRequests
+
+	var errorRatio, slowRatio float64
+	if total > 0 {
+		errorRatio = float64(failed) / float64(total)
+		slowRatio = float64(slow) / float64(total)
+	}
+
+	m.transition(key, &stats.errors, now,
+		enough && m.cfg.errorRatio > 0 && errorRatio > m.cfg.errorRatio,
+		HighErrorRateAlertName,
+		fmt.Sprintf("%.1f%% of %s requests to %s failed with 5xx", errorRatio*100, key.method, key.route),
+		errorRatio)
+
+	m.transition(key, &stats.latency, now,
+		enough && m.cfg.latency > 0 && slowRatio > m.cfg.slowRatio,
+		HighLatencyAlertName,
+		fmt.Sprintf("%.1f%% of %s requests to %s took longer than %s", slowRatio*100, key.method, key.route, m.cfg.latency),
+		slowRatio)
+}
+
+// transition fires, re-sends or resolves an alert depending on whether its threshold is breached.
+func (m *RouteMonitor) transition(key routeKey, state *alertState, now time.Time, breached bool, alertName, summary string, ratio float64) {
+	switch {
+	case breached && !state.firing:
+		state.firing = true
+		state.startsAt = now
+	case breached && now.Sub(state.lastSent) < m.cfg.resendInterval:
+		return
+	case !breached && state.firing:
+		state.firing = false
+		m.emitter.enqueue(m.alert(key, state, alertName, summary, ratio, WithEndsAt(now)))
+		return
+	case !breached:
+		return
+	}
+
+	state.lastSent = now
+	m.emitter.enqueue(m.alert(key, state, alertName, summary, ratio))
+}
+
+func (m *RouteMonitor) alert(key routeKey, state *alertState, alertName, summary string, ratio float64, options ...AlertOption) *Alert {
+	alertOptions := append([]AlertOption{
+		WithLabel("alertname", alertName),
+		WithLabel("route", key.route),
+		WithLabel("method", key.method),
+		WithAnnotation(SummaryAnnotation, summary),
+		WithAnnotation("ratio", strconv.FormatFloat(ratio, 'f', 4, 64)),
+		WithStartsAt(state.startsAt),
+	}, m.cfg.alertOptions...)
+	return NewAlert(append(alertOptions, options...)...)
+}
+
+func defaultRoute(r *http.Request) string {
+	if r.Pattern == "" {
+		return UnmatchedRoute
+	}
+	return r.Pattern
+}
+
+type routeKey struct {
+	route  string
+	method string
+}
+
+type routeStats struct {
+	window  *slidingWindow
+	errors  alertState
+	latency alertState
+}
+
+type alertState struct {
+	firing   bool
+	startsAt time.Time
+	lastSent time.Time
+}
+
+// slidingWindow counts requests in a fixed number of time buckets covering the window duration.
+type slidingWindow struct {
+	width   time.Duration
+	buckets [windowBuckets]windowBucket
+}
+
+type windowBucket struct {
+	start  time.Time
+	total  int
+	failed int
+	slow   int
+}
+
+func newSlidingWindow(window time.Duration) *slidingWindow {
+	return &slidingWindow{width: window / windowBuckets}
+}
+
+func (w *slidingWindow) add(now time.Time, failed, slow bool) {
+	start := now.Truncate(w.width)
+	b := &w.buckets[(start.UnixNano()/int64(w.width))%windowBuckets]
+	if !b.start.Equal(start) {
+		*b = windowBucket{start: start}
+	}
+
+	b.total++
+	if failed {
+		b.failed++
+	}
+	if slow {
+		b.slow++
+	}
+}
+
+func (w *slidingWindow) sum(now time.Time) (total, failed, slow int) {
+	oldest := now.Truncate(w.width).Add(-w.width * (windowBuckets - 1))
+	for _, b := range w.buckets {
+		if b.start.Before(oldest) {
+			continue
+		}
+		total += b.total
+		failed += b.failed
+		slow += b.slow
+	}
+	return total, failed, slow
+}
+
+// statusWriter records the status code written by a handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for streaming handlers, e.g. server-sent events.
+func (w *statusWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker for handlers that take over the connection, e.g. websockets.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap allows http.ResponseController to access the underlying ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,281 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// fakeClock is a manually advanced clock for RouteMonitor tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func withClock(clock *fakeClock) RouteMonitorOption {
+	return func(c *routeMonitorConfig) {
+		c.now = clock.Now
+	}
+}
+
+func TestRouteMonitor(t *testing.T) {
+	tests := []struct {
+		name            string
+		options         []RouteMonitorOption
+		requests        func(clock *fakeClock, serve func(path string))
+		expectedAlerts  []string // alertname of each alert, in order
+		expectResolved  []bool
+		expectedRoute   string
+		expectedMethod  string
+		expectedService string
+	}{
+		{
+			name: "error rate fires and resolves",
+			options: []RouteMonitorOption{
+				WithMinRequests(4),
+				WithErrorRatioThreshold(0.5),
+				WithRouteAlertOptions(WithLabel("service", "api")),
+			},
+			requests: func(clock *fakeClock, serve func(path string)) {
+				for range 4 {
+					serve("/users/1?fail=1")
+				}
+				clock.Advance(10 * time.Minute)
+				serve("/users/1")
+			},
+			expectedAlerts:  []string{HighErrorRateAlertName, HighErrorRateAlertName},
+			expectResolved:  []bool{false, true},
+			expectedRoute:   "GET /users/{id}",
+			expectedMethod:  http.MethodGet,
+			expectedService: "api",
+		},
+		{
+			name: "below minimum requests",
+			options: []RouteMonitorOption{
+				WithMinRequests(10),
+			},
+			requests: func(clock *fakeClock, serve func(path string)) {
+				for range 5 {
+					serve("/users/1?fail=1")
+				}
+			},
+		},
+		{
+			name: "below error threshold",
+			options: []RouteMonitorOption{
+				WithMinRequests(4),
+				WithErrorRatioThreshold(0.5),
+			},
+			requests: func(clock *fakeClock, serve func(path string)) {
+				serve("/users/1?fail=1")
+				for range 3 {
+					serve("/users/1")
+				}
+			},
+		},
+		{
+			name: "resend while firing",
+			options: []RouteMonitorOption{
+				WithMinRequests(1),
+				WithResendInterval(time.Minute),
+			},
+			requests: func(clock *fakeClock, serve func(path string)) {
+				serve("/users/1?fail=1")
+				serve("/users/1?fail=1")
+				clock.Advance(2 * time.Minute)
+				serve("/users/1?fail=1")
+			},
+			expectedAlerts: []string{HighErrorRateAlertName, HighErrorRateAlertName},
+			expectResolved: []bool{false, false},
+			expectedRoute:  "GET /users/{id}",
+			expectedMethod: http.MethodGet,
+		},
+		{
+			name: "slow requests fire latency alert",
+			options: []RouteMonitorOption{
+				WithMinRequests(2),
+				WithLatencyThreshold(100*time.Millisecond, 0.5),
+			},
+			requests: func(clock *fakeClock, serve func(path string)) {
+				serve("/users/1?slow=1")
+				serve("/users/1?slow=1")
+			},
+			expectedAlerts: []string{HighLatencyAlertName},
+			expectResolved: []bool{false},
+			expectedRoute:  "GET /users/{id}",
+			expectedMethod: http.MethodGet,
+		},
+		{
+			name: "unmatched route",
+			options: []RouteMonitorOption{
+				WithMinRequests(1),
+			},
+			requests: func(clock *fakeClock, serve func(path string)) {
+				serve("/unknown")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newAlertRecorder(t)
+			am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			monitor := NewRouteMonitor(am, append(tt.options, withClock(clock))...)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("slow") {
+					clock.Advance(200 * time.Millisecond)
+				}
+				if r.URL.Query().Has("fail") {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			})
+			handler := monitor.Middleware(mux)
+
+			tt.requests(clock, func(path string) {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := monitor.Close(ctx); err != nil {
+				t.Fatalf("failed to close monitor: %v", err)
+			}
+
+			alerts := recorder.received()
+			if len(alerts) != len(tt.expectedAlerts) {
+				t.Fatalf("expected %d alerts, got %d: %v", len(tt.expectedAlerts), len(alerts), alerts)
+			}
+			for i, alert := range alerts {
+				if alert.Labels["alertname"] != tt.expectedAlerts[i] {
+					t.Errorf("expected alert %d to be %s, got %s", i, tt.expectedAlerts[i], alert.Labels["alertname"])
+				}
+				if resolved := alert.EndsAt != nil; resolved != tt.expectResolved[i] {
+					t.Errorf("expected alert %d resolved=%v, got %v", i, tt.expectResolved[i], resolved)
+				}
+				if alert.Labels["route"] != tt.expectedRoute {
+					t.Errorf("expected route label %q, got %q", tt.expectedRoute, alert.Labels["route"])
+				}
+				if alert.Labels["method"] != tt.expectedMethod {
+					t.Errorf("expected method label %q, got %q", tt.expectedMethod, alert.Labels["method"])
+				}
+				if alert.Labels["service"] != tt.expectedService {
+					t.Errorf("expected service label %q, got %q", tt.expectedService, alert.Labels["service"])
+				}
+			}
+		})
+	}
+}
+
+func TestRouteMonitorShortWindowAndClose(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	monitor := NewRouteMonitor(am, WithWindow(time.Nanosecond), WithMinRequests(1))
+	if monitor.cfg.window != minWindow {
+		t.Errorf("expected window %s, got %s", minWindow, monitor.cfg.window)
+	}
+
+	handler := monitor.Middleware(http.NotFoundHandler())
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Go(func() {
+			if err := monitor.Close(ctx); err != nil {
+				t.Errorf("failed to close monitor: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestRouteMonitorResponseWriter(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	monitor := NewRouteMonitor(am, WithMinRequests(1), WithErrorRatioThreshold(0.5))
+	defer monitor.Close(context.Background())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Error("expected ResponseWriter to implement http.Flusher")
+			return
+		}
+		w.Write([]byte("data: 1\n\n"))
+		flusher.Flush()
+	})
+	mux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("expected ResponseWriter to implement http.Hijacker")
+			return
+		}
+		conn, buf, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("failed to hijack connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		buf.Flush()
+	})
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	server := httptest.NewServer(monitor.Middleware(mux))
+	defer server.Close()
+
+	for _, path := range []string{"/stream", "/ws", "/panic"} {
+		resp, err := http.Get(server.URL + path)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	stats, ok := monitor.routes[routeKey{route: "GET /panic", method: http.MethodGet}]
+	if !ok {
+		t.Fatal("expected panicking request to be observed")
+	}
+	// the client may retry the aborted request, so it can be observed more than once
+	if total, failed, _ := stats.window.sum(monitor.cfg.now()); total == 0 || failed != total {
+		t.Errorf("expected all requests to fail, got %d of %d", failed, total)
+	}
+}
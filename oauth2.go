@@ -0,0 +1,106 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuth2 configures the OAuth 2.0 client credentials flow used to authenticate to Alertmanager.
+type OAuth2 struct {
+	ClientID         string            `yaml:"client_id"`
+	ClientSecret     string            `yaml:"client_secret,omitempty"`
+	ClientSecretFile string            `yaml:"client_secret_file,omitempty"`
+	TokenURL         string            `yaml:"token_url"`
+	Scopes           []string          `yaml:"scopes,omitempty"`
+	EndpointParams   map[string]string `yaml:"endpoint_params,omitempty"`
+}
+
+// WithOAuth2 authenticates requests with tokens obtained using the OAuth 2.0 client credentials flow.
+// Tokens are requested through the same transport as Alertmanager requests, with the context of the
+// request that needs them and the timeout of the HTTP client, or DefaultConfigTimeout if it has none.
+// Tokens are refreshed before they expire. If ClientSecretFile is set, the client secret is read from it.
+func WithOAuth2(cfg OAuth2) ManagerOption {
+	return func(a *Alertmanager) error {
+		if cfg.ClientSecretFile != "" {
+			secret, err := readSecretFile(cfg.ClientSecretFile)
+			if err != nil {
+				return err
+			}
+			cfg.ClientSecret = secret
+		}
+
+		params := url.Values{}
+		for k, v := range cfg.EndpointParams {
+			params.Set(k, v)
+		}
+		cc := &clientcredentials.Config{
+			ClientID:       cfg.ClientID,
+			ClientSecret:   cfg.ClientSecret,
+			TokenURL:       cfg.TokenURL,
+			Scopes:         cfg.Scopes,
+			EndpointParams: params,
+		}
+
+		a.middleware = append(a.middleware, func(next http.RoundTripper) http.RoundTripper {
+			// middleware is built after all options were applied, so the timeout is final
+			timeout := a.client.Timeout
+			if timeout <= 0 {
+				timeout = DefaultConfigTimeout
+			}
+			return &oauth2Transport{
+				cfg:    cc,
+				client: &http.Client{Transport: next, Timeout: timeout},
+				next:   next,
+			}
+		})
+		return nil
+	}
+}
+
+// oauth2Transport adds an OAuth 2.0 token to requests. Unlike oauth2.Transport, it fetches tokens
+// with the context of the request, so that they are canceled together.
+type oauth2Transport struct {
+	cfg    *clientcredentials.Config
+	client *http.Client
+	next   http.RoundTripper
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (t *oauth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.getToken(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	// a RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	token.SetAuthHeader(req)
+	return t.next.RoundTrip(req)
+}
+
+// getToken returns the cached token, or fetches a new one if it is missing or about to expire.
+func (t *oauth2Transport) getToken(ctx context.Context) (*oauth2.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.Valid() {
+		return t.token, nil
+	}
+
+	token, err := t.cfg.Token(context.WithValue(ctx, oauth2.HTTPClient, t.client))
+	if err != nil {
+		return nil, err
+	}
+	t.token = token
+	return token, nil
+}
@@ -0,0 +1,81 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestWithOAuth2(t *testing.T) {
+	var tokenRequests atomic.Int32
+	block := make(chan struct{})
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("scope") == "blocked" {
+			<-block
+			return
+		}
+		tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+	defer close(block)
+
+	var gotAuth atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth.Store(r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("token is sent and reused", func(t *testing.T) {
+		am, err := NewAlertmanager(logr.Discard(), &http.Client{},
+			WithEndpoint(server.URL),
+			WithOAuth2(OAuth2{ClientID: "id", ClientSecret: "secret", TokenURL: tokenServer.URL}))
+		if err != nil {
+			t.Fatalf("failed to create alertmanager: %v", err)
+		}
+
+		for range 2 {
+			resp, err := am.Emit(NewAlert(WithLabel("alertname", "test")))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+		}
+
+		if got := gotAuth.Load(); got != "Bearer token" {
+			t.Errorf("expected Authorization header %q, got %v", "Bearer token", got)
+		}
+		if got := tokenRequests.Load(); got != 1 {
+			t.Errorf("expected 1 token request, got %d", got)
+		}
+	})
+
+	t.Run("token request uses the request context", func(t *testing.T) {
+		am, err := NewAlertmanager(logr.Discard(), &http.Client{},
+			WithEndpoint(server.URL),
+			WithOAuth2(OAuth2{ClientID: "id", ClientSecret: "secret", TokenURL: tokenServer.URL, Scopes: []string{"blocked"}}))
+		if err != nil {
+			t.Fatalf("failed to create alertmanager: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = am.EmitContext(ctx, NewAlert(WithLabel("alertname", "test")))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error %v, got %v", context.DeadlineExceeded, err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected token request to be canceled with the request, took %s", elapsed)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	// DefaultPanicAlertName is the alertname label of alerts created from recovered panics.
+	DefaultPanicAlertName = "GoroutinePanic"
+
+	// PanicAnnotation is the annotation holding the recovered panic value.
+	PanicAnnotation = "panic"
+
+	// StackAnnotation is the annotation holding the stack trace of a recovered panic.
+	StackAnnotation = "stack"
+
+	// DefaultRecoverTimeout is the default timeout for sending the alert for a recovered panic.
+	DefaultRecoverTimeout = 2 * time.Second
+
+	// maxStackSize is the maximum size of the stack trace sent in the stack annotation.
+	maxStackSize = 16 << 10
+)
+
+// RecoverOption represents a configuration option for RecoverAndAlert, Go and RecoverHandler.
+type RecoverOption func(*recoverConfig)
+
+type recoverConfig struct {
+	timeout      time.Duration
+	repanic      bool
+	alertOptions []AlertOption
+}
+
+// WithRecoverTimeout sets the timeout for sending the alert for a recovered panic.
+// Defaults to DefaultRecoverTimeout.
+func WithRecoverTimeout(timeout time.Duration) RecoverOption {
+	return func(c *recoverConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithRepanic sets whether the panic is re-raised after the alert was sent. Defaults to true.
+// When false, the panic is swallowed; RecoverHandler then responds with 500 Internal Server Error.
+func WithRepanic(repanic bool) RecoverOption {
+	return func(c *recoverConfig) {
+		c.repanic = repanic
+	}
+}
+
+// WithPanicAlertOptions sets options applied to the alert for a recovered panic, e.g. to add labels
+// or override the default alertname and severity labels.
+func WithPanicAlertOptions(options ...AlertOption) RecoverOption {
+	return func(c *recoverConfig) {
+		c.alertOptions = append(c.alertOptions, options...)
+	}
+}
+
+func newRecoverConfig(options []RecoverOption) *recoverConfig {
+	cfg := &recoverConfig{
+		timeout: DefaultRecoverTimeout,
+		repanic: true,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// RecoverAndAlert recovers a panic, sends a critical alert with the panic value and stack trace,
+// and then re-panics unless WithRepanic(false) is set. It must be deferred directly:
+//
+//	defer alertmanager.RecoverAndAlert(ctx, am)
+//
+// The alert is sent synchronously with a short timeout that is independent of the cancellation of ctx.
+func RecoverAndAlert(ctx context.Context, am Emitter, options ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	cfg := newRecoverConfig(options)
+	alertPanic(ctx, am, v, cfg)
+	if cfg.repanic {
+		panic(v)
+	}
+}
+
+// Go runs fn in a new goroutine that alerts on panics, see RecoverAndAlert.
+func Go(ctx context.Context, am Emitter, fn func(), options ...RecoverOption) {
+	go func() {
+		defer RecoverAndAlert(ctx, am, options...)
+		fn()
+	}()
+}
+
+// RecoverHandler wraps an http.Handler so that panics in next are sent as alerts, see RecoverAndAlert.
+// Panics with http.ErrAbortHandler are re-raised without an alert, since they abort a request on purpose.
+func RecoverHandler(am Emitter, next http.Handler, options ...RecoverOption) http.Handler {
+	cfg := newRecoverConfig(options)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			alertPanic(r.Context(), am, v, cfg, WithAnnotation("request", r.Method+" "+r.URL.Path))
+			if cfg.repanic {
+				panic(v)
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// alertPanic sends a critical alert for the recovered panic value v.
+func alertPanic(ctx context.Context, am Emitter, v any, cfg *recoverConfig, options ...AlertOption) {
+	if am == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	if len(stack) > maxStackSize {
+		stack = stack[:maxStackSize]
+	}
+
+	alertOptions := append([]AlertOption{
+		WithLabel("alertname", DefaultPanicAlertName),
+		WithLabel("severity", "critical"),
+		WithAnnotation(PanicAnnotation, fmt.Sprint(v)),
+		WithAnnotation(StackAnnotation, string(stack)),
+		WithStartsAt(time.Now()),
+	}, options...)
+	alert := NewAlert(append(alertOptions, cfg.alertOptions...)...)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.timeout)
+	defer cancel()
+
+	resp, err := am.EmitContext(ctx, alert)
+	if err != nil {
+		emitterLogger(am).Error(err, "failed to send alert for recovered panic", PanicAnnotation, fmt.Sprint(v))
+		return
+	}
+	resp.Body.Close()
+}
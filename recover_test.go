@@ -0,0 +1,173 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestRecoverAndAlert(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []RecoverOption
+		fn            func()
+		expectAlert   bool
+		expectRepanic bool
+		expectedLabel map[string]string
+	}{
+		{
+			name:          "panic is alerted and re-raised",
+			fn:            func() { panic("boom") },
+			expectAlert:   true,
+			expectRepanic: true,
+			expectedLabel: map[string]string{"alertname": DefaultPanicAlertName, "severity": "critical"},
+		},
+		{
+			name:          "panic is swallowed",
+			options:       []RecoverOption{WithRepanic(false)},
+			fn:            func() { panic("boom") },
+			expectAlert:   true,
+			expectedLabel: map[string]string{"alertname": DefaultPanicAlertName},
+		},
+		{
+			name: "custom alert options",
+			options: []RecoverOption{
+				WithRepanic(false),
+				WithPanicAlertOptions(WithLabel("severity", "warning"), WithLabel("worker", "sync")),
+			},
+			fn:            func() { panic("boom") },
+			expectAlert:   true,
+			expectedLabel: map[string]string{"severity": "warning", "worker": "sync"},
+		},
+		{
+			name: "no panic",
+			fn:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newAlertRecorder(t)
+			am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			var repanicked any
+			func() {
+				defer func() { repanicked = recover() }()
+				func() {
+					defer RecoverAndAlert(context.Background(), am, tt.options...)
+					tt.fn()
+				}()
+			}()
+
+			if (repanicked != nil) != tt.expectRepanic {
+				t.Errorf("expected re-panic=%v, got %v", tt.expectRepanic, repanicked)
+			}
+
+			alerts := recorder.received()
+			if !tt.expectAlert {
+				if len(alerts) != 0 {
+					t.Errorf("expected no alerts, got %d", len(alerts))
+				}
+				return
+			}
+			if len(alerts) != 1 {
+				t.Fatalf("expected 1 alert, got %d", len(alerts))
+			}
+
+			for k, v := range tt.expectedLabel {
+				if alerts[0].Labels[k] != v {
+					t.Errorf("expected label %s=%s, got %s", k, v, alerts[0].Labels[k])
+				}
+			}
+			if alerts[0].Annotations[PanicAnnotation] != "boom" {
+				t.Errorf("expected panic annotation 'boom', got %q", alerts[0].Annotations[PanicAnnotation])
+			}
+			if !strings.Contains(alerts[0].Annotations[StackAnnotation], "goroutine") {
+				t.Errorf("expected stack annotation to contain a stack trace")
+			}
+		})
+	}
+}
+
+func TestGo(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	Go(context.Background(), am, func() {
+		defer wg.Done()
+		panic("worker crashed")
+	}, WithRepanic(false))
+	wg.Wait()
+
+	// the alert is sent after the deferred wg.Done, so wait for the goroutine to finish sending
+	for range 100 {
+		if len(recorder.received()) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	alerts := recorder.received()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Annotations[PanicAnnotation] != "worker crashed" {
+		t.Errorf("expected panic annotation 'worker crashed', got %q", alerts[0].Annotations[PanicAnnotation])
+	}
+}
+
+func TestRecoverHandler(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	handler := RecoverHandler(am, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler crashed")
+	}), WithRepanic(false))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+
+	alerts := recorder.received()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Annotations["request"] != "GET /users" {
+		t.Errorf("expected request annotation 'GET /users', got %q", alerts[0].Annotations["request"])
+	}
+
+	abort := RecoverHandler(am, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	func() {
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Errorf("expected http.ErrAbortHandler to be re-raised, got %v", v)
+			}
+		}()
+		abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+	if len(recorder.received()) != 1 {
+		t.Errorf("expected no alert for http.ErrAbortHandler")
+	}
+}
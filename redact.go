@@ -0,0 +1,191 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// redactedValue replaces secrets in String, LogValue and MarshalLog output and in logged payloads.
+const redactedValue = "<redacted>"
+
+var (
+	_ fmt.Stringer   = Args{}
+	_ slog.LogValuer = Args{}
+	_ logr.Marshaler = Args{}
+
+	_ fmt.Stringer   = (*Alertmanager)(nil)
+	_ slog.LogValuer = (*Alertmanager)(nil)
+	_ logr.Marshaler = (*Alertmanager)(nil)
+)
+
+// WithSensitiveKeys masks the values of the given label and annotation keys in logged alert payloads,
+// i.e. the debug logs of sent alerts and the log of dry runs without a writer. The values are sent to Alertmanager unchanged.
+func WithSensitiveKeys(keys ...string) ManagerOption {
+	return func(a *Alertmanager) error {
+		if a.sensitiveKeys == nil {
+			a.sensitiveKeys = make(map[string]bool, len(keys))
+		}
+		for _, key := range keys {
+			a.sensitiveKeys[key] = true
+		}
+		return nil
+	}
+}
+
+// plainArgs has the fields of Args without its methods, so it can be formatted without recursion.
+type plainArgs Args
+
+// String returns the Args with secrets redacted, so they can be printed safely.
+func (a Args) String() string {
+	return fmt.Sprintf("%+v", a.redacted())
+}
+
+// LogValue implements slog.LogValuer, logging the Args with secrets redacted.
+func (a Args) LogValue() slog.Value {
+	return structLogValue(a.redacted())
+}
+
+// MarshalLog implements logr.Marshaler, logging the Args with secrets redacted.
+func (a Args) MarshalLog() any {
+	return a.redacted()
+}
+
+// redacted returns a copy of the Args with the password and the credentials in URLs redacted.
+func (a Args) redacted() plainArgs {
+	p := plainArgs(a)
+	if p.Password != "" {
+		p.Password = redactedValue
+	}
+	p.AlertmanagerURL = redactURL(p.AlertmanagerURL)
+	p.ProxyURL = redactURL(p.ProxyURL)
+	return p
+}
+
+// clientInfo describes the configuration of an Alertmanager client without its secrets.
+type clientInfo struct {
+	Endpoint    string
+	Auth        string `json:",omitempty"`
+	TenantID    string `json:",omitempty"`
+	ProxyURL    string `json:",omitempty"`
+	DryRun      bool
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// String returns the configuration of the client with secrets redacted, so it can be printed safely.
+// Only the type of the credentials, e.g. Basic or Bearer, is included.
+func (a *Alertmanager) String() string {
+	if a == nil {
+		return "disabled"
+	}
+	return fmt.Sprintf("%+v", a.info())
+}
+
+// LogValue implements slog.LogValuer, logging the configuration of the client with secrets redacted.
+func (a *Alertmanager) LogValue() slog.Value {
+	if a == nil {
+		return slog.StringValue("disabled")
+	}
+	return structLogValue(a.info())
+}
+
+// MarshalLog implements logr.Marshaler, logging the configuration of the client with secrets redacted.
+func (a *Alertmanager) MarshalLog() any {
+	if a == nil {
+		return "disabled"
+	}
+	return a.info()
+}
+
+func (a *Alertmanager) info() clientInfo {
+	info := clientInfo{
+		Endpoint:    a.displayURL,
+		TenantID:    a.tenantID,
+		DryRun:      a.dryRun != nil,
+		Labels:      a.mask(a.labels),
+		Annotations: a.mask(a.annotations),
+	}
+
+	header := a.authHeader
+	if a.authFile != nil {
+		header = a.authFormat("")
+	}
+	info.Auth, _, _ = strings.Cut(header, " ")
+
+	if a.proxyURL != nil {
+		info.ProxyURL = redactURL(a.proxyURL.String())
+	}
+	return info
+}
+
+// mask returns a copy of labels or annotations with the values of sensitive keys redacted.
+func (a *Alertmanager) mask(values map[string]string) map[string]string {
+	masked := make(map[string]string, len(values))
+	for k, v := range values {
+		if a.sensitiveKeys[k] {
+			v = redactedValue
+		}
+		masked[k] = v
+	}
+	return masked
+}
+
+// logPayload returns the JSON payload of alerts for logging, with the values of sensitive keys redacted.
+func (a *Alertmanager) logPayload(body []byte, alerts []Alert) string {
+	if len(a.sensitiveKeys) == 0 {
+		return string(body)
+	}
+
+	masked := make([]Alert, len(alerts))
+	for i, alert := range alerts {
+		alert.Labels = a.mask(alert.Labels)
+		alert.Annotations = a.mask(alert.Annotations)
+		masked[i] = alert
+	}
+
+	body, err := json.Marshal(masked)
+	if err != nil {
+		return redactedValue
+	}
+	return string(body)
+}
+
+// redactURL replaces the credentials in a URL, i.e. the username and password, if any.
+// If s cannot be parsed as a URL with a host, everything up to the last @ is treated as credentials.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	switch {
+	case err == nil && u.User != nil:
+		u.User = nil
+		scheme, rest, _ := strings.Cut(u.String(), "//")
+		return scheme + "//" + redactedValue + "@" + rest
+	case err == nil && !strings.Contains(u.Opaque, "@"):
+		return s
+	}
+
+	i := strings.LastIndex(s, "@")
+	if i < 0 {
+		return s
+	}
+	if scheme, _, found := strings.Cut(s[:i], "://"); found {
+		return scheme + "://" + redactedValue + s[i:]
+	}
+	return redactedValue + s[i:]
+}
+
+// structLogValue returns a group value with an attribute for every field of a struct.
+func structLogValue(v any) slog.Value {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+	attrs := make([]slog.Attr, 0, rt.NumField())
+	for i := range rt.NumField() {
+		attrs = append(attrs, slog.Any(rt.Field(i).Name, rv.Field(i).Interface()))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,183 @@
+package alertmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// DefaultWatchInterval is the default interval at which ReloadableAlertmanager.WatchFiles checks files for changes.
+const DefaultWatchInterval = 30 * time.Second
+
+// ErrNilReloadFunc is returned when a nil ReloadFunc is provided.
+var ErrNilReloadFunc = errors.New("reload function cannot be nil")
+
+// ReloadFunc creates a new Alertmanager client, e.g. from Args or a config file read again.
+// A nil client with a nil error disables sending alerts, like NewAlertmanagerWithArgs.
+// It may pass the same http.Client to every call, since NewAlertmanager configures a copy of it.
+type ReloadFunc func() (*Alertmanager, error)
+
+// ReloadableAlertmanager is an Emitter whose Alertmanager client can be replaced at runtime,
+// e.g. to change the endpoint, credentials, TLS configuration or base labels without a restart.
+//
+// Each Emit call uses the client that is current when it starts, so in-flight requests complete
+// with the previous client. Alerts queued by helpers such as NewAlertSink that are given the
+// ReloadableAlertmanager are sent with the client that is current when they are sent.
+// ReloadableAlertmanager is safe for concurrent use.
+type ReloadableAlertmanager struct {
+	current atomic.Pointer[Alertmanager]
+	reload  ReloadFunc
+	log     logr.Logger
+
+	// mu serializes reloads
+	mu sync.Mutex
+}
+
+var _ Emitter = (*ReloadableAlertmanager)(nil)
+
+// NewReloadableAlertmanager creates a ReloadableAlertmanager using a client created by reload,
+// which is called again by Reload. Pass the same Metrics to every client created by reload,
+// since metrics can only be registered once.
+func NewReloadableAlertmanager(logger logr.Logger, reload ReloadFunc) (*ReloadableAlertmanager, error) {
+	if reload == nil {
+		return nil, ErrNilReloadFunc
+	}
+
+	am, err := reload()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ReloadableAlertmanager{reload: reload, log: logger}
+	r.current.Store(am)
+	return r, nil
+}
+
+// ConfigFileReloadFunc returns a ReloadFunc that creates a client from the config file at path,
+// including the secrets in its _file fields, using NewAlertmanagerFromConfig.
+func ConfigFileReloadFunc(logger logr.Logger, path string, options ...ManagerOption) ReloadFunc {
+	return func() (*Alertmanager, error) {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewAlertmanagerFromConfig(logger, cfg, options...)
+	}
+}
+
+// Current returns the current client, or nil if sending alerts is disabled.
+func (r *ReloadableAlertmanager) Current() *Alertmanager {
+	return r.current.Load()
+}
+
+// Emit sends one or more alerts using the current client.
+func (r *ReloadableAlertmanager) Emit(alerts ...*Alert) (*http.Response, error) {
+	return r.current.Load().Emit(alerts...)
+}
+
+// EmitContext sends one or more alerts using the current client and the provided context.
+func (r *ReloadableAlertmanager) EmitContext(ctx context.Context, alerts ...*Alert) (*http.Response, error) {
+	return r.current.Load().EmitContext(ctx, alerts...)
+}
+
+// Reload creates a new client and replaces the current one. If the new client cannot be created,
+// the current client is kept and the error is returned.
+func (r *ReloadableAlertmanager) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	am, err := r.reload()
+	if err != nil {
+		return fmt.Errorf("failed to reload Alertmanager client: %w", err)
+	}
+	r.swap(am)
+	return nil
+}
+
+// Update replaces the current client with am. A nil am disables sending alerts.
+func (r *ReloadableAlertmanager) Update(am *Alertmanager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.swap(am)
+}
+
+// swap stores am and releases the idle connections of the previous client.
+// In-flight requests of the previous client are not affected. Must be called with r.mu held.
+func (r *ReloadableAlertmanager) swap(am *Alertmanager) {
+	old := r.current.Swap(am)
+	if old != nil && old != am && (am == nil || old.client != am.client) {
+		old.client.CloseIdleConnections()
+	}
+
+	if am == nil {
+		r.log.Info("reloaded Alertmanager client; sending alerts is disabled")
+		return
+	}
+	r.log.Info("reloaded Alertmanager client", logKeyEndpoint, am.displayURL)
+}
+
+// WatchFiles calls Reload whenever the content of any of the files at paths changes, until ctx is done.
+// Files are checked every interval, or every DefaultWatchInterval if interval is not positive.
+// Polling the content also detects the atomic symlink swaps used by Kubernetes ConfigMap and Secret
+// volumes. Errors reading the files or reloading are logged and the current client is kept.
+func (r *ReloadableAlertmanager) WatchFiles(ctx context.Context, interval time.Duration, paths ...string) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	last, err := hashFiles(paths)
+	if err != nil {
+		r.log.Error(err, "failed to read watched files")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		sum, err := hashFiles(paths)
+		if err != nil {
+			r.log.Error(err, "failed to read watched files")
+			continue
+		}
+		if sum == last {
+			continue
+		}
+
+		if err := r.Reload(); err != nil {
+			r.log.Error(err, "keeping current Alertmanager client")
+			continue
+		}
+		last = sum
+	}
+}
+
+// hashFiles returns a hash of the content of the files at paths.
+func hashFiles(paths []string) ([sha256.Size]byte, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+		fileSum := sha256.Sum256(content)
+		h.Write(fileSum[:])
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
@@ -0,0 +1,232 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestReloadableAlertmanager(t *testing.T) {
+	first := newAlertRecorder(t)
+	second := newAlertRecorder(t)
+
+	var endpoint atomic.Value
+	endpoint.Store(first.URL)
+	reloadErr := errors.New("reload failed")
+	var fail atomic.Bool
+
+	r, err := NewReloadableAlertmanager(logr.Discard(), func() (*Alertmanager, error) {
+		if fail.Load() {
+			return nil, reloadErr
+		}
+		return NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(endpoint.Load().(string)))
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	emit := func() {
+		t.Helper()
+		resp, err := r.Emit(NewAlert(WithLabel("alertname", "Test")))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	emit()
+	endpoint.Store(second.URL)
+	if err := r.Reload(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	emit()
+
+	fail.Store(true)
+	if err := r.Reload(); !errors.Is(err, reloadErr) {
+		t.Errorf("expected reload error, got %v", err)
+	}
+	emit()
+
+	if got := len(first.received()); got != 1 {
+		t.Errorf("expected 1 alert before reload, got %d", got)
+	}
+	if got := len(second.received()); got != 2 {
+		t.Errorf("expected 2 alerts after reload, got %d", got)
+	}
+
+	r.Update(nil)
+	if r.Current() != nil {
+		t.Error("expected no current client")
+	}
+	emit()
+	if got := len(second.received()); got != 2 {
+		t.Errorf("expected no alerts while disabled, got %d", got-2)
+	}
+}
+
+func TestReloadableAlertmanagerSharedClient(t *testing.T) {
+	recorder := newAlertRecorder(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	var calls []string
+	client := &http.Client{}
+	endpoint := strings.Replace(recorder.URL, "http://", "http://admin:s3cret@", 1)
+	r, err := NewReloadableAlertmanager(logr.FromSlogHandler(logger.Handler()), func() (*Alertmanager, error) {
+		return NewAlertmanager(logr.Discard(), client, WithEndpoint(endpoint), WithInsecure(true),
+			WithRoundTripper(recordingMiddleware("middleware", &calls)))
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for range 3 {
+		if err := r.Reload(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	resp, err := r.Emit(NewAlert(WithLabel("alertname", "Test")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(calls) != 1 {
+		t.Errorf("expected middleware to be called once after reloads, got %d", len(calls))
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("expected credentials to be redacted in reload logs, got %s", buf.String())
+	}
+}
+
+func TestNewReloadableAlertmanagerErrors(t *testing.T) {
+	if _, err := NewReloadableAlertmanager(logr.Discard(), nil); !errors.Is(err, ErrNilReloadFunc) {
+		t.Errorf("expected ErrNilReloadFunc, got %v", err)
+	}
+
+	reloadErr := errors.New("reload failed")
+	_, err := NewReloadableAlertmanager(logr.Discard(), func() (*Alertmanager, error) {
+		return nil, reloadErr
+	})
+	if !errors.Is(err, reloadErr) {
+		t.Errorf("expected reload error, got %v", err)
+	}
+}
+
+func TestReloadableAlertmanagerConcurrentEmit(t *testing.T) {
+	first := newAlertRecorder(t)
+	second := newAlertRecorder(t)
+
+	var n atomic.Int64
+	r, err := NewReloadableAlertmanager(logr.Discard(), func() (*Alertmanager, error) {
+		endpoint := first.URL
+		if n.Add(1)%2 == 0 {
+			endpoint = second.URL
+		}
+		return NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(endpoint))
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				resp, err := r.Emit(NewAlert(WithLabel("alertname", "Test")))
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	for range 10 {
+		if err := r.Reload(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+	wg.Wait()
+
+	if got := len(first.received()) + len(second.received()); got != 40 {
+		t.Errorf("expected 40 alerts, got %d", got)
+	}
+}
+
+func TestReloadableAlertmanagerWatchFiles(t *testing.T) {
+	first := newAlertRecorder(t)
+	second := newAlertRecorder(t)
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "alertmanager.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	target := func(server string) string {
+		u, _ := url.Parse(server)
+		return "static_configs: [{targets: ['" + u.Host + "']}]\n"
+	}
+
+	writeConfig(target(first.URL))
+	r, err := NewReloadableAlertmanager(logr.Discard(), ConfigFileReloadFunc(logr.Discard(), configPath))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- r.WatchFiles(ctx, 10*time.Millisecond, configPath)
+	}()
+
+	initial := r.Current()
+	writeConfig("static_configs: []\n")
+	time.Sleep(50 * time.Millisecond)
+	if r.Current() != initial {
+		t.Error("expected invalid config to keep the current client")
+	}
+
+	writeConfig(target(second.URL))
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Current() == initial && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp, err := r.Emit(NewAlert(WithLabel("alertname", "Test")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if got := len(second.received()); got != 1 {
+		t.Errorf("expected 1 alert after config change, got %d", got)
+	}
+	if got := len(first.received()); got != 0 {
+		t.Errorf("expected no alerts to previous endpoint, got %d", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
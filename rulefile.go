@@ -0,0 +1,180 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"go.yaml.in/yaml/v3"
+)
+
+// QueryFunc evaluates the expression of a rule at the given time and returns the resulting samples,
+// e.g. by querying an in-process metrics registry. Every returned sample is an active alert.
+type QueryFunc func(ctx context.Context, expr string, ts time.Time) ([]Sample, error)
+
+// RuleGroups is the content of a Prometheus rule file.
+type RuleGroups struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a group of rules in a Prometheus rule file.
+// Limit is the maximum number of alerts of each alerting rule; an evaluation that exceeds it fails
+// and resolves all alerts of the rule. QueryOffset shifts the evaluation time of the group's expressions into the past.
+type RuleGroup struct {
+	Name        string            `yaml:"name"`
+	Interval    model.Duration    `yaml:"interval,omitempty"`
+	QueryOffset model.Duration    `yaml:"query_offset,omitempty"`
+	Limit       int               `yaml:"limit,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Rules       []RuleDefinition  `yaml:"rules"`
+}
+
+// RuleDefinition is an alerting or recording rule in a Prometheus rule file.
+// Recording rules are ignored.
+type RuleDefinition struct {
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           model.Duration    `yaml:"for,omitempty"`
+	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
+
+// LoadRuleFile reads and parses a Prometheus rule file.
+func LoadRuleFile(path string) (*RuleGroups, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %w", err)
+	}
+
+	groups, err := ParseRuleFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule file %s: %w", path, err)
+	}
+	return groups, nil
+}
+
+// ParseRuleFile parses and validates the content of a Prometheus rule file.
+// All validation errors are returned at once.
+func ParseRuleFile(content []byte) (*RuleGroups, error) {
+	var groups RuleGroups
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&groups); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+
+	if err := groups.Validate(); err != nil {
+		return nil, err
+	}
+	return &groups, nil
+}
+
+// Validate checks the rule groups for missing or duplicate names, missing expressions
+// and invalid templates. All problems are returned at once as a joined error.
+func (g *RuleGroups) Validate() error {
+	var errs []error
+	groupNames := make(map[string]bool)
+
+	for i, group := range g.Groups {
+		if group.Name == "" {
+			errs = append(errs, fmt.Errorf("groups[%d]: name required", i))
+		} else if groupNames[group.Name] {
+			errs = append(errs, fmt.Errorf("groups[%d]: duplicate group name %q", i, group.Name))
+		}
+		groupNames[group.Name] = true
+		if group.Limit < 0 {
+			errs = append(errs, fmt.Errorf("groups[%d]: limit must not be negative", i))
+		}
+
+		for j, def := range group.Rules {
+			path := fmt.Sprintf("groups[%d].rules[%d]", i, j)
+			switch {
+			case def.Record != "" && def.Alert != "":
+				errs = append(errs, fmt.Errorf("%s: only one of record and alert may be set", path))
+			case def.Record == "" && def.Alert == "":
+				errs = append(errs, fmt.Errorf("%s: one of record and alert required", path))
+			}
+			if def.Expr == "" {
+				errs = append(errs, fmt.Errorf("%s: expr required", path))
+			}
+			if def.Record != "" {
+				continue
+			}
+
+			if _, err := parseTemplates("label", def.Labels); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+			if _, err := parseTemplates("annotation", def.Annotations); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Rules converts the alerting rules of all groups into Rules whose conditions evaluate
+// their expressions using query. Group intervals, query offsets, limits and labels are applied
+// to their rules. Rules are identified by their group and position, e.g. "disk/1", so that
+// several rules may share an alert name, as in Prometheus.
+func (g *RuleGroups) Rules(query QueryFunc) []Rule {
+	var rules []Rule
+	for _, group := range g.Groups {
+		for i, def := range group.Rules {
+			if def.Alert == "" {
+				continue
+			}
+
+			labels := maps.Clone(group.Labels)
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			maps.Copy(labels, def.Labels)
+
+			expr, offset := def.Expr, time.Duration(group.QueryOffset)
+			rules = append(rules, Rule{
+				Name: def.Alert,
+				ID:   fmt.Sprintf("%s/%d", group.Name, i),
+				Condition: func(ctx context.Context) ([]Sample, error) {
+					return query(ctx, expr, EvaluationTime(ctx).Add(-offset))
+				},
+				For:           time.Duration(def.For),
+				KeepFiringFor: time.Duration(def.KeepFiringFor),
+				Interval:      time.Duration(group.Interval),
+				Limit:         group.Limit,
+				Labels:        labels,
+				Annotations:   maps.Clone(def.Annotations),
+			})
+		}
+	}
+	return rules
+}
+
+// LoadRuleFiles loads Prometheus rule files and registers their alerting rules, whose
+// expressions are evaluated using query. All files are checked first, and no rules are registered
+// if any file cannot be loaded; the errors of all files are returned at once.
+// Group names must be unique across all files, since rules are identified by group and position.
+func (m *RuleManager) LoadRuleFiles(query QueryFunc, paths ...string) error {
+	var rules []Rule
+	var errs []error
+	for _, path := range paths {
+		groups, err := LoadRuleFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, groups.Rules(query)...)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return m.register(rules...)
+}
@@ -0,0 +1,225 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const testRuleFile = `
+groups:
+  - name: disk
+    interval: 30s
+    query_offset: 1m
+    limit: 10
+    labels:
+      team: storage
+    rules:
+      - record: instance:disk_usage:ratio
+        expr: disk_used / disk_total
+      - alert: DiskAlmostFull
+        expr: instance:disk_usage:ratio > 0.9
+        for: 5m
+        keep_firing_for: 1m
+        labels:
+          severity: warning
+          mountpoint: '{{ $labels.mountpoint }}'
+        annotations:
+          summary: 'Disk {{ $labels.mountpoint }} is {{ $value }} full'
+      - alert: DiskAlmostFull
+        expr: instance:disk_usage:ratio > 0.95
+        labels:
+          severity: critical
+`
+
+func TestParseRuleFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		expectedRules  int
+		expectedErrors []string
+	}{
+		{
+			name:          "valid rule file",
+			content:       testRuleFile,
+			expectedRules: 2,
+		},
+		{
+			name: "invalid rules",
+			content: `
+groups:
+  - name: a
+    rules:
+      - alert: NoExpr
+      - record: both
+        alert: Both
+        expr: up
+  - name: a
+    rules:
+      - expr: up
+      - alert: BadTemplate
+        expr: up
+        annotations:
+          summary: '{{ $labels.instance'
+`,
+			expectedErrors: []string{
+				"groups[0].rules[0]: expr required",
+				"groups[0].rules[1]: only one of record and alert may be set",
+				"groups[1]: duplicate group name \"a\"",
+				"groups[1].rules[0]: one of record and alert required",
+				"groups[1].rules[1]: invalid template annotation summary",
+			},
+		},
+		{
+			name:           "negative limit",
+			content:        "groups:\n  - name: a\n    limit: -1\n    rules: []\n",
+			expectedErrors: []string{"groups[0]: limit must not be negative"},
+		},
+		{
+			name:           "unknown field",
+			content:        "groups:\n  - name: a\n    rulez: []\n",
+			expectedErrors: []string{"failed to parse rule file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := ParseRuleFile([]byte(tt.content))
+			if len(tt.expectedErrors) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if rules := groups.Rules(nil); len(rules) != tt.expectedRules {
+					t.Errorf("expected %d rules, got %d", tt.expectedRules, len(rules))
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			for _, expected := range tt.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleGroupsRules(t *testing.T) {
+	groups, err := ParseRuleFile([]byte(testRuleFile))
+	if err != nil {
+		t.Fatalf("failed to parse rule file: %v", err)
+	}
+
+	var queried string
+	var queriedAt time.Time
+	samples := []Sample{{Value: 1}}
+	rules := groups.Rules(func(_ context.Context, expr string, ts time.Time) ([]Sample, error) {
+		queried, queriedAt = expr, ts
+		return samples, nil
+	})
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+
+	rule := rules[0]
+	if rule.Name != "DiskAlmostFull" || rule.ID != "disk/1" {
+		t.Errorf("expected rule DiskAlmostFull with ID disk/1, got %s with ID %s", rule.Name, rule.ID)
+	}
+	if rules[1].Name != "DiskAlmostFull" || rules[1].ID != "disk/2" {
+		t.Errorf("expected rule DiskAlmostFull with ID disk/2, got %s with ID %s", rules[1].Name, rules[1].ID)
+	}
+	if rule.For != 5*time.Minute || rule.KeepFiringFor != time.Minute || rule.Interval != 30*time.Second {
+		t.Errorf("unexpected durations: for %s, keep firing for %s, interval %s", rule.For, rule.KeepFiringFor, rule.Interval)
+	}
+	if rule.Limit != 10 {
+		t.Errorf("expected limit 10, got %d", rule.Limit)
+	}
+	if rule.Labels["team"] != "storage" || rule.Labels["severity"] != "warning" {
+		t.Errorf("expected group and rule labels, got %v", rule.Labels)
+	}
+
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := rule.Condition(context.WithValue(context.Background(), evaluationTimeKey{}, ts)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if queried != "instance:disk_usage:ratio > 0.9" {
+		t.Errorf("expected rule expression to be queried, got %q", queried)
+	}
+	if expected := ts.Add(-time.Minute); !queriedAt.Equal(expected) {
+		t.Errorf("expected query at evaluation time minus offset %s, got %s", expected, queriedAt)
+	}
+}
+
+func TestRuleManagerLoadRuleFiles(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	if err := os.WriteFile(path, []byte(testRuleFile), 0o600); err != nil {
+		t.Fatalf("failed to write rule file: %v", err)
+	}
+
+	query := func(context.Context, string, time.Time) ([]Sample, error) {
+		return []Sample{{Labels: map[string]string{"mountpoint": "/var"}, Value: 0.95}}, nil
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("groups:\n  - rules: []\n"), 0o600); err != nil {
+		t.Fatalf("failed to write rule file: %v", err)
+	}
+
+	m := NewRuleManager(am)
+	err = m.LoadRuleFiles(query, path, filepath.Join(dir, "missing.yaml"), invalid)
+	if err == nil {
+		t.Fatal("expected error for missing and invalid rule files, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to read rule file") || !strings.Contains(err.Error(), "groups[0]: name required") {
+		t.Errorf("expected errors of all rule files, got %v", err)
+	}
+	if len(m.rules) != 0 {
+		t.Errorf("expected no rules to be registered, got %d", len(m.rules))
+	}
+
+	if err := m.LoadRuleFiles(query, path); err != nil {
+		t.Fatalf("failed to load rule file: %v", err)
+	}
+	if len(m.rules) != 2 {
+		t.Fatalf("expected 2 rules sharing an alert name, got %d", len(m.rules))
+	}
+	if err := m.LoadRuleFiles(query, path); !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("expected error %v, got %v", ErrDuplicateRule, err)
+	}
+	if len(m.rules) != 2 {
+		t.Errorf("expected no rules to be added by a failed load, got %d", len(m.rules))
+	}
+
+	t0 := time.Now()
+	m.evaluate(context.Background(), m.rules["disk/1"], t0)
+	m.evaluate(context.Background(), m.rules["disk/1"], t0.Add(5*time.Minute))
+
+	alerts := recorder.received()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	alert := alerts[0]
+	if alert.Labels["mountpoint"] != "/var" || alert.Labels["team"] != "storage" || alert.Labels["alertname"] != "DiskAlmostFull" {
+		t.Errorf("unexpected labels %v", alert.Labels)
+	}
+	if expected := "Disk /var is 0.95 full"; alert.Annotations["summary"] != expected {
+		t.Errorf("expected summary %q, got %q", expected, alert.Annotations["summary"])
+	}
+}
@@ -0,0 +1,530 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// DefaultEvaluationInterval is the default interval at which rules are evaluated.
+	DefaultEvaluationInterval = time.Minute
+
+	// DefaultResendDelay is the default minimum delay between sending the same alert twice.
+	DefaultResendDelay = time.Minute
+
+	// resolvedRetention is how long resolved alerts are kept and re-sent every resend delay, as in Prometheus.
+	resolvedRetention = 15 * time.Minute
+)
+
+var (
+	// ErrRuleNameRequired is returned when a rule without a name is registered.
+	ErrRuleNameRequired = errors.New("invalid rule: name required")
+
+	// ErrRuleConditionRequired is returned when a rule without a condition is registered.
+	ErrRuleConditionRequired = errors.New("invalid rule: condition required")
+
+	// ErrRuleManagerRunning is returned when Run is called on a RuleManager that is already running.
+	ErrRuleManagerRunning = errors.New("rule manager is already running")
+
+	// ErrDuplicateRule is returned when a rule with the same ID, or name if it has no ID, is already registered.
+	ErrDuplicateRule = errors.New("invalid rule: duplicate rule name")
+)
+
+// Sample is a value returned by a Condition, identified by its labels.
+type Sample struct {
+	// Labels are added to the labels of the alert for this sample.
+	Labels map[string]string
+
+	// Value is the value of the sample, e.g. the value that crossed a threshold.
+	Value float64
+}
+
+// Condition evaluates an alerting condition. Every returned sample is an active alert;
+// return no samples if the condition is not met. Use EvaluationTime to get the time of the evaluation.
+type Condition func(ctx context.Context) ([]Sample, error)
+
+// evaluationTimeKey is the context key of the evaluation time of a rule.
+type evaluationTimeKey struct{}
+
+// EvaluationTime returns the time of the rule evaluation that ctx belongs to,
+// or the current time if ctx does not belong to a rule evaluation.
+func EvaluationTime(ctx context.Context) time.Time {
+	if ts, ok := ctx.Value(evaluationTimeKey{}).(time.Time); ok {
+		return ts
+	}
+	return time.Now()
+}
+
+// BoolCondition returns a Condition that is met when f returns true.
+func BoolCondition(f func(ctx context.Context) (bool, error)) Condition {
+	return func(ctx context.Context) ([]Sample, error) {
+		ok, err := f(ctx)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []Sample{{Value: 1}}, nil
+	}
+}
+
+// ValueCondition returns a Condition that is met when the value returned by f satisfies active,
+// e.g. func(v float64) bool { return v > 0.9 }.
+func ValueCondition(f func(ctx context.Context) (float64, error), active func(value float64) bool) Condition {
+	return func(ctx context.Context) ([]Sample, error) {
+		value, err := f(ctx)
+		if err != nil || !active(value) {
+			return nil, err
+		}
+		return []Sample{{Value: value}}, nil
+	}
+}
+
+// Rule is an in-process alerting rule, modeled after Prometheus alerting rules.
+type Rule struct {
+	// Name is the alertname label of the alerts created by the rule.
+	Name string
+
+	// ID identifies the rule in the RuleManager, e.g. for Unregister. If empty, Name is used,
+	// so that rules without an ID must have unique names.
+	ID string
+
+	// Condition is evaluated at every interval.
+	Condition Condition
+
+	// For is how long a condition must be met before the alert fires. Until then it is pending.
+	For time.Duration
+
+	// KeepFiringFor is how long an alert keeps firing after its condition is no longer met.
+	KeepFiringFor time.Duration
+
+	// Interval is the evaluation interval of the rule. If zero, the RuleManager's interval is used.
+	Interval time.Duration
+
+	// Limit is the maximum number of alerts of the rule. If zero, the number is not limited.
+	// An evaluation that exceeds it fails and resolves all alerts of the rule, as in Prometheus.
+	Limit int
+
+	// Severity is added as the severity label, if set.
+	Severity string
+
+	// Labels are added to all alerts created by the rule.
+	// Values are expanded as templates with $labels (the sample labels) and $value, as in Prometheus.
+	Labels map[string]string
+
+	// Annotations are added to all alerts created by the rule.
+	// Values are expanded as templates with $labels (the alert labels) and $value, as in Prometheus.
+	Annotations map[string]string
+}
+
+// AlertState is the state of an alert created by a rule.
+type AlertState int
+
+const (
+	// StateInactive is the state of an alert whose condition is not met.
+	StateInactive AlertState = iota
+
+	// StatePending is the state of an alert whose condition is met for less than the rule's For duration.
+	StatePending
+
+	// StateFiring is the state of an alert that is sent to Alertmanager.
+	StateFiring
+)
+
+// String returns the name of the state.
+func (s AlertState) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateFiring:
+		return "firing"
+	default:
+		return "inactive"
+	}
+}
+
+// RuleManagerOption represents a configuration option for a RuleManager.
+type RuleManagerOption func(*RuleManager)
+
+// WithEvaluationInterval sets the interval of rules without their own interval.
+// Defaults to DefaultEvaluationInterval.
+func WithEvaluationInterval(interval time.Duration) RuleManagerOption {
+	return func(m *RuleManager) {
+		m.interval = interval
+	}
+}
+
+// WithResendDelay sets the minimum delay between sending the same alert twice.
+// Defaults to DefaultResendDelay.
+func WithResendDelay(delay time.Duration) RuleManagerOption {
+	return func(m *RuleManager) {
+		m.resendDelay = delay
+	}
+}
+
+// WithRuleSendTimeout sets the timeout for sending the alerts of a rule evaluation.
+// Defaults to DefaultSendTimeout.
+func WithRuleSendTimeout(timeout time.Duration) RuleManagerOption {
+	return func(m *RuleManager) {
+		m.sendTimeout = timeout
+	}
+}
+
+// RuleManager evaluates registered rules at their intervals and drives firing and resolved
+// transitions into Alertmanager, the way the Prometheus rule manager does: alerts are pending
+// until their condition was met for the rule's For duration, are re-sent while firing, and are
+// sent as resolved once their condition is no longer met.
+type RuleManager struct {
+	am          Emitter
+	log         logr.Logger
+	interval    time.Duration
+	resendDelay time.Duration
+	sendTimeout time.Duration
+
+	mu      sync.Mutex
+	rules   map[string]*ruleState
+	running context.Context
+	wg      sync.WaitGroup
+}
+
+// NewRuleManager creates a RuleManager that sends alerts using am.
+func NewRuleManager(am Emitter, options ...RuleManagerOption) *RuleManager {
+	m := &RuleManager{
+		am:          am,
+		log:         emitterLogger(am),
+		interval:    DefaultEvaluationInterval,
+		resendDelay: DefaultResendDelay,
+		sendTimeout: DefaultSendTimeout,
+		rules:       make(map[string]*ruleState),
+	}
+	for _, opt := range options {
+		opt(m)
+	}
+	return m
+}
+
+// Register adds a rule. If the RuleManager is running, the rule is evaluated immediately.
+func (m *RuleManager) Register(rule Rule) error {
+	return m.register(rule)
+}
+
+// register adds all rules, or none of them if any rule is invalid or already registered.
+func (m *RuleManager) register(rules ...Rule) error {
+	states := make([]*ruleState, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return ErrRuleNameRequired
+		}
+		if rule.Condition == nil {
+			return fmt.Errorf("%w: %s", ErrRuleConditionRequired, rule.Name)
+		}
+		rs, err := newRuleState(rule)
+		if err != nil {
+			return err
+		}
+		states = append(states, rs)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	added := make(map[string]bool, len(states))
+	for _, rs := range states {
+		id := rs.rule.id()
+		if _, ok := m.rules[id]; ok || added[id] {
+			return fmt.Errorf("%w: %s", ErrDuplicateRule, id)
+		}
+		added[id] = true
+	}
+
+	for _, rs := range states {
+		m.rules[rs.rule.id()] = rs
+		if m.running != nil {
+			m.start(rs)
+		}
+	}
+	return nil
+}
+
+// Unregister removes the rule with the given ID, or name if it has no ID, and stops evaluating it.
+// Alerts of the rule that are firing are not resolved and expire in Alertmanager.
+func (m *RuleManager) Unregister(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if rs, ok := m.rules[name]; ok {
+		if rs.cancel != nil {
+			rs.cancel()
+		}
+		delete(m.rules, name)
+	}
+}
+
+// Run evaluates all rules at their intervals until ctx is done.
+func (m *RuleManager) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running != nil {
+		m.mu.Unlock()
+		return ErrRuleManagerRunning
+	}
+	m.running = ctx
+	for _, rs := range m.rules {
+		m.start(rs)
+	}
+	m.mu.Unlock()
+
+	<-ctx.Done()
+	m.wg.Wait()
+
+	m.mu.Lock()
+	m.running = nil
+	m.mu.Unlock()
+	return nil
+}
+
+// start starts the evaluation loop of a rule. Must be called with m.mu held.
+func (m *RuleManager) start(rs *ruleState) {
+	ctx, cancel := context.WithCancel(m.running)
+	rs.cancel = cancel
+
+	interval := rs.rule.Interval
+	if interval <= 0 {
+		interval = m.interval
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			m.evaluate(ctx, rs, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// evaluate evaluates a rule at ts, updates the states of its alerts and sends the alerts that are due.
+func (m *RuleManager) evaluate(ctx context.Context, rs *ruleState, ts time.Time) {
+	samples, err := rs.rule.Condition(context.WithValue(ctx, evaluationTimeKey{}, ts))
+	if err != nil {
+		m.log.Error(err, "failed to evaluate rule", "rule", rs.rule.Name)
+		return
+	}
+
+	var alerts []*ruleAlert
+	if limit := rs.rule.Limit; limit > 0 && len(samples) > limit {
+		m.log.Error(fmt.Errorf("exceeded limit of %d with %d alerts", limit, len(samples)),
+			"failed to evaluate rule", "rule", rs.rule.Name)
+		alerts = rs.resolveAll(ts)
+	} else {
+		alerts = rs.update(samples, ts)
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	m.send(ctx, rs, alerts, ts)
+}
+
+// send sends the alerts of a rule that are due, i.e. resolved since they were last sent or not sent
+// within the resend delay. Alerts that fail to send are retried at the next evaluation.
+func (m *RuleManager) send(ctx context.Context, rs *ruleState, alerts []*ruleAlert, ts time.Time) {
+	interval := rs.rule.Interval
+	if interval <= 0 {
+		interval = m.interval
+	}
+	validUntil := ts.Add(4 * max(interval, m.resendDelay))
+
+	var sent []*ruleAlert
+	var due []*Alert
+	for _, a := range alerts {
+		if !a.needsSending(ts, m.resendDelay) {
+			continue
+		}
+		sent = append(sent, a)
+		due = append(due, rs.alert(a, validUntil))
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.sendTimeout)
+	defer cancel()
+
+	resp, err := m.am.EmitContext(ctx, due...)
+	if err != nil {
+		m.log.Error(err, "failed to send rule alerts", "rule", rs.rule.Name, logKeyAlertCount, len(due))
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		m.log.Error(errors.New(resp.Status), "Alertmanager rejected rule alerts",
+			"rule", rs.rule.Name, logKeyAlertCount, len(due), logKeyStatus, resp.StatusCode)
+		return
+	}
+	for _, a := range sent {
+		a.lastSent = ts
+	}
+}
+
+// ruleState holds the alerts of a rule, keyed by the fingerprint of their sample labels.
+type ruleState struct {
+	rule                Rule
+	labelTemplates      map[string]*template.Template
+	annotationTemplates map[string]*template.Template
+	alerts              map[string]*ruleAlert
+	cancel              context.CancelFunc
+}
+
+// id returns the key of the rule in the RuleManager.
+func (r Rule) id() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.Name
+}
+
+func newRuleState(rule Rule) (*ruleState, error) {
+	labelTemplates, err := parseTemplates("label", rule.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %s: %w", rule.Name, err)
+	}
+	annotationTemplates, err := parseTemplates("annotation", rule.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %s: %w", rule.Name, err)
+	}
+
+	return &ruleState{
+		rule:                rule,
+		labelTemplates:      labelTemplates,
+		annotationTemplates: annotationTemplates,
+		alerts:              make(map[string]*ruleAlert),
+	}, nil
+}
+
+type ruleAlert struct {
+	labels     map[string]string
+	value      float64
+	state      AlertState
+	activeAt   time.Time
+	lastSeen   time.Time
+	resolvedAt time.Time
+	lastSent   time.Time
+}
+
+// needsSending reports whether the alert must be sent at ts, as in Prometheus: resolved alerts are sent
+// immediately once, and all alerts are re-sent once the resend delay since they were last sent has passed.
+func (a *ruleAlert) needsSending(ts time.Time, resendDelay time.Duration) bool {
+	if a.state == StateInactive && a.lastSent.Before(a.resolvedAt) {
+		return true
+	}
+	return ts.Sub(a.lastSent) >= resendDelay
+}
+
+// update applies the samples of an evaluation at ts and returns the alerts that are firing
+// or were resolved recently.
+func (rs *ruleState) update(samples []Sample, ts time.Time) []*ruleAlert {
+	seen := make(map[string]bool, len(samples))
+	for _, s := range samples {
+		fp := fingerprint(s.Labels)
+		seen[fp] = true
+
+		a, ok := rs.alerts[fp]
+		if !ok || a.state == StateInactive {
+			a = &ruleAlert{labels: maps.Clone(s.Labels), state: StatePending, activeAt: ts}
+			rs.alerts[fp] = a
+		}
+		a.value = s.Value
+		a.lastSeen = ts
+		if a.state == StatePending && ts.Sub(a.activeAt) >= rs.rule.For {
+			a.state = StateFiring
+		}
+	}
+
+	var active []*ruleAlert
+	for fp, a := range rs.alerts {
+		if !seen[fp] {
+			switch {
+			case a.state == StatePending:
+				delete(rs.alerts, fp)
+				continue
+			case a.state == StateFiring && ts.Sub(a.lastSeen) < rs.rule.KeepFiringFor:
+			case a.state == StateFiring:
+				a.state = StateInactive
+				a.resolvedAt = ts
+			case ts.Sub(a.resolvedAt) > resolvedRetention:
+				delete(rs.alerts, fp)
+				continue
+			}
+		}
+
+		if a.state == StateFiring || a.state == StateInactive {
+			active = append(active, a)
+		}
+	}
+
+	return active
+}
+
+// resolveAll resolves the firing alerts and drops the pending alerts of the rule at ts,
+// e.g. when its limit is exceeded, and returns the alerts that were resolved recently.
+func (rs *ruleState) resolveAll(ts time.Time) []*ruleAlert {
+	var resolved []*ruleAlert
+	for fp, a := range rs.alerts {
+		switch a.state {
+		case StatePending:
+			delete(rs.alerts, fp)
+			continue
+		case StateFiring:
+			a.state = StateInactive
+			a.resolvedAt = ts
+		}
+		resolved = append(resolved, a)
+	}
+	return resolved
+}
+
+// alert converts a rule alert into an Alert. Firing alerts are valid until validUntil.
+func (rs *ruleState) alert(a *ruleAlert, validUntil time.Time) *Alert {
+	alert := NewAlert(WithStartsAt(a.activeAt))
+	maps.Copy(alert.Labels, a.labels)
+	expandTemplates(alert.Labels, rs.labelTemplates, templateData{Labels: a.labels, Value: a.value})
+	alert.Labels["alertname"] = rs.rule.Name
+	if rs.rule.Severity != "" {
+		alert.Labels["severity"] = rs.rule.Severity
+	}
+	expandTemplates(alert.Annotations, rs.annotationTemplates, templateData{Labels: alert.Labels, Value: a.value})
+
+	if a.state == StateInactive {
+		WithEndsAt(a.resolvedAt)(alert)
+	} else {
+		WithEndsAt(validUntil)(alert)
+	}
+	return alert
+}
+
+// fingerprint returns a string that uniquely identifies a label set.
+func fingerprint(labels map[string]string) string {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		b.WriteString(k)
+		b.WriteByte(0xff)
+		b.WriteString(labels[k])
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
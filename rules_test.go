@@ -0,0 +1,348 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestRuleManagerTransitions(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type step struct {
+		offset         time.Duration
+		active         bool
+		expectedAlerts int
+		expectResolved bool
+	}
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		steps []step
+	}{
+		{
+			name: "pending then firing then resolved",
+			rule: Rule{For: 2 * time.Minute},
+			steps: []step{
+				{offset: 0, active: true},
+				{offset: time.Minute, active: true},
+				{offset: 2 * time.Minute, active: true, expectedAlerts: 1},
+				{offset: 2*time.Minute + 30*time.Second, active: true},
+				{offset: 3 * time.Minute, active: true, expectedAlerts: 1},
+				{offset: 4 * time.Minute, active: false, expectedAlerts: 1, expectResolved: true},
+				{offset: 4*time.Minute + 30*time.Second, active: false},
+				{offset: 5 * time.Minute, active: false, expectedAlerts: 1, expectResolved: true},
+				{offset: 4*time.Minute + resolvedRetention + time.Second, active: false},
+			},
+		},
+		{
+			name: "pending alert is dropped without firing",
+			rule: Rule{For: 5 * time.Minute},
+			steps: []step{
+				{offset: 0, active: true},
+				{offset: time.Minute, active: false},
+				{offset: 2 * time.Minute, active: true},
+				{offset: 6 * time.Minute, active: true},
+				{offset: 7 * time.Minute, active: true, expectedAlerts: 1},
+			},
+		},
+		{
+			name: "keep firing for",
+			rule: Rule{KeepFiringFor: 3 * time.Minute},
+			steps: []step{
+				{offset: 0, active: true, expectedAlerts: 1},
+				{offset: 30 * time.Second, active: false},
+				{offset: 2 * time.Minute, active: false, expectedAlerts: 1},
+				{offset: 3 * time.Minute, active: false, expectedAlerts: 1, expectResolved: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newAlertRecorder(t)
+			am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			var active atomic.Bool
+			rule := tt.rule
+			rule.Name = "TestRule"
+			rule.Severity = "warning"
+			rule.Labels = map[string]string{"team": "edge"}
+			rule.Condition = BoolCondition(func(context.Context) (bool, error) {
+				return active.Load(), nil
+			})
+
+			m := NewRuleManager(am)
+			if err := m.Register(rule); err != nil {
+				t.Fatalf("failed to register rule: %v", err)
+			}
+
+			sent := 0
+			for i, s := range tt.steps {
+				active.Store(s.active)
+				m.evaluate(context.Background(), m.rules[rule.Name], t0.Add(s.offset))
+
+				alerts := recorder.received()[sent:]
+				sent += len(alerts)
+				if len(alerts) != s.expectedAlerts {
+					t.Fatalf("step %d: expected %d alerts, got %d", i, s.expectedAlerts, len(alerts))
+				}
+				for _, alert := range alerts {
+					if alert.Labels["alertname"] != "TestRule" || alert.Labels["severity"] != "warning" || alert.Labels["team"] != "edge" {
+						t.Errorf("step %d: unexpected labels %v", i, alert.Labels)
+					}
+					if alert.EndsAt == nil {
+						t.Fatalf("step %d: expected EndsAt to be set", i)
+					}
+					resolved := !alert.EndsAt.After(t0.Add(s.offset))
+					if resolved != s.expectResolved {
+						t.Errorf("step %d: expected resolved=%v, got EndsAt %v", i, s.expectResolved, alert.EndsAt)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRuleManagerSamples(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	m := NewRuleManager(am)
+	err = m.Register(Rule{
+		Name: "DiskFull",
+		Condition: func(context.Context) ([]Sample, error) {
+			return []Sample{
+				{Labels: map[string]string{"mountpoint": "/"}, Value: 0.95},
+				{Labels: map[string]string{"mountpoint": "/var"}, Value: 0.99},
+			}, nil
+		},
+		Annotations: map[string]string{"summary": "disk almost full"},
+	})
+	if err != nil {
+		t.Fatalf("failed to register rule: %v", err)
+	}
+
+	m.evaluate(context.Background(), m.rules["DiskFull"], time.Now())
+
+	alerts := recorder.received()
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(alerts))
+	}
+	mountpoints := map[string]bool{}
+	for _, alert := range alerts {
+		mountpoints[alert.Labels["mountpoint"]] = true
+		if alert.Annotations["summary"] != "disk almost full" {
+			t.Errorf("expected summary annotation, got %v", alert.Annotations)
+		}
+	}
+	if !mountpoints["/"] || !mountpoints["/var"] {
+		t.Errorf("expected alerts for both mountpoints, got %v", mountpoints)
+	}
+}
+
+func TestRuleManagerRegister(t *testing.T) {
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint("http://alertmanager:9093"))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+	condition := BoolCondition(func(context.Context) (bool, error) { return false, nil })
+
+	tests := []struct {
+		name        string
+		rule        Rule
+		expectedErr error
+	}{
+		{
+			name: "valid rule",
+			rule: Rule{Name: "Valid", Condition: condition},
+		},
+		{
+			name:        "missing name",
+			rule:        Rule{Condition: condition},
+			expectedErr: ErrRuleNameRequired,
+		},
+		{
+			name:        "missing condition",
+			rule:        Rule{Name: "NoCondition"},
+			expectedErr: ErrRuleConditionRequired,
+		},
+		{
+			name:        "duplicate name",
+			rule:        Rule{Name: "Valid", Condition: condition},
+			expectedErr: ErrDuplicateRule,
+		},
+	}
+
+	m := NewRuleManager(am)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Register(tt.rule)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRuleManagerRun(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	m := NewRuleManager(am, WithEvaluationInterval(10*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	err = m.Register(Rule{
+		Name:      "AlwaysFiring",
+		Condition: BoolCondition(func(context.Context) (bool, error) { return true, nil }),
+	})
+	if err != nil {
+		t.Fatalf("failed to register rule: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(recorder.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.received()) == 0 {
+		t.Errorf("expected alert to be sent while running")
+	}
+}
+
+func TestRuleManagerRetriesFailedSend(t *testing.T) {
+	var fail atomic.Bool
+	var resolved atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var alerts []Alert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Errorf("failed to decode alerts: %v", err)
+		}
+		for _, alert := range alerts {
+			if alert.EndsAt != nil && alert.EndsAt.Before(time.Now()) {
+				resolved.Add(1)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	var active atomic.Bool
+	m := NewRuleManager(am, WithResendDelay(time.Hour))
+	if err := m.Register(Rule{Name: "TestRule", Condition: BoolCondition(func(context.Context) (bool, error) {
+		return active.Load(), nil
+	})}); err != nil {
+		t.Fatalf("failed to register rule: %v", err)
+	}
+	rs := m.rules["TestRule"]
+
+	t0 := time.Now().Add(-time.Hour)
+	active.Store(true)
+	m.evaluate(context.Background(), rs, t0)
+
+	active.Store(false)
+	fail.Store(true)
+	m.evaluate(context.Background(), rs, t0.Add(time.Minute))
+	if got := resolved.Load(); got != 0 {
+		t.Fatalf("expected no resolved alerts while Alertmanager fails, got %d", got)
+	}
+
+	fail.Store(false)
+	m.evaluate(context.Background(), rs, t0.Add(2*time.Minute))
+	if got := resolved.Load(); got != 1 {
+		t.Errorf("expected failed resolution to be retried, got %d resolved alerts", got)
+	}
+}
+
+func TestRuleManagerLimit(t *testing.T) {
+	recorder := newAlertRecorder(t)
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	var n atomic.Int32
+	var evaluatedAt atomic.Value
+	m := NewRuleManager(am)
+	if err := m.Register(Rule{Name: "TestRule", Limit: 1, Condition: func(ctx context.Context) ([]Sample, error) {
+		evaluatedAt.Store(EvaluationTime(ctx))
+		samples := make([]Sample, n.Load())
+		for i := range samples {
+			samples[i] = Sample{Labels: map[string]string{"instance": strconv.Itoa(i)}, Value: 1}
+		}
+		return samples, nil
+	}}); err != nil {
+		t.Fatalf("failed to register rule: %v", err)
+	}
+	rs := m.rules["TestRule"]
+
+	t0 := time.Now().Add(-time.Hour)
+	steps := []struct {
+		samples          int32
+		expectedFiring   int
+		expectedResolved int
+	}{
+		{samples: 1, expectedFiring: 1},
+		{samples: 2, expectedResolved: 1},
+		{samples: 1, expectedFiring: 1},
+	}
+
+	sent := 0
+	for i, s := range steps {
+		ts := t0.Add(time.Duration(i) * time.Minute)
+		n.Store(s.samples)
+		m.evaluate(context.Background(), rs, ts)
+
+		if got := evaluatedAt.Load(); got != ts {
+			t.Errorf("step %d: expected evaluation time %s, got %v", i, ts, got)
+		}
+
+		alerts := recorder.received()[sent:]
+		sent += len(alerts)
+		firing, resolved := 0, 0
+		for _, alert := range alerts {
+			if alert.EndsAt.After(ts) {
+				firing++
+			} else {
+				resolved++
+			}
+		}
+		if firing != s.expectedFiring || resolved != s.expectedResolved {
+			t.Errorf("step %d: expected %d firing and %d resolved alerts, got %d and %d",
+				i, s.expectedFiring, s.expectedResolved, firing, resolved)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package alertmanager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretFile is a secret read from a file. The file is re-read when its modification time or size
+// changes, so rotated secrets, e.g. from Kubernetes Secret volumes, are used without a restart.
+type secretFile struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// newSecretFile reads the secret from the file at path.
+func newSecretFile(path string) (*secretFile, error) {
+	s := &secretFile{path: path}
+	if _, err := s.get(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// get returns the secret, re-reading the file if it changed. If the file cannot be read,
+// the previously read secret is returned along with the error.
+func (s *secretFile) get() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return s.value, fmt.Errorf("failed to read secret file: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.value, nil
+	}
+
+	value, err := readSecretFile(s.path)
+	if err != nil {
+		return s.value, err
+	}
+	s.value = value
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	return s.value, nil
+}
+
+// readSecretFile reads a secret from a file, trimming surrounding whitespace such as a trailing newline.
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,293 @@
+package alertmanager
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultLogAlertName is the alertname label of alerts created from log records.
+	DefaultLogAlertName = "LoggedError"
+
+	// DefaultAlertMarkerKey is the log attribute that marks a record as an alert, e.g. alert=true.
+	DefaultAlertMarkerKey = "alert"
+
+	// SummaryAnnotation is the annotation holding the log message of alerts created from log records.
+	SummaryAnnotation = "summary"
+
+	// DefaultAlertHandlerQueueName is the queue label of the queue metrics of an AlertHandler.
+	DefaultAlertHandlerQueueName = "alert_handler"
+)
+
+// AlertHandler is a slog.Handler that forwards log records to another handler and additionally
+// sends matching records to Alertmanager as alerts. By default, records at slog.LevelError or
+// above with the attribute alert=true are sent.
+//
+// Alerts are delivered asynchronously and rate limited so that logging never blocks on HTTP.
+// The log message is sent as the summary annotation, attributes selected with WithLabelAttrs
+// as labels, and all other attributes as annotations.
+// Call Close to deliver queued alerts before the program exits.
+type AlertHandler struct {
+	next    slog.Handler
+	emitter *asyncEmitter
+	cfg     *alertHandlerConfig
+
+	// attrs are the attributes added with WithAttrs, qualified by their group
+	attrs  []slog.Attr
+	groups []string
+}
+
+type alertHandlerConfig struct {
+	async      asyncConfig
+	level      slog.Leveler
+	markerKey  string
+	filter     func(context.Context, slog.Record) bool
+	labelAttrs []string
+	alertName  string
+}
+
+// AlertHandlerOption represents a configuration option for an AlertHandler.
+type AlertHandlerOption func(*alertHandlerConfig)
+
+// WithAlertLevel sets the minimum level of records sent as alerts. Defaults to slog.LevelError.
+func WithAlertLevel(level slog.Leveler) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.level = level
+	}
+}
+
+// WithAlertMarker sets the boolean attribute that must be true for a record to be sent as an alert.
+// Defaults to DefaultAlertMarkerKey. The key is matched regardless of the groups the attribute is in.
+// An empty key sends all records at or above the alert level.
+func WithAlertMarker(key string) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.markerKey = key
+	}
+}
+
+// WithAlertFilter sets an additional predicate that a record must satisfy to be sent as an alert.
+func WithAlertFilter(filter func(ctx context.Context, record slog.Record) bool) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.filter = filter
+	}
+}
+
+// WithLabelAttrs sets the attributes sent as alert labels. Attributes in groups are
+// referenced by their dotted path, e.g. "request.method". All other attributes are sent as annotations.
+func WithLabelAttrs(keys ...string) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.labelAttrs = keys
+	}
+}
+
+// WithLogAlertName sets the alertname label of alerts created from log records.
+// Defaults to DefaultLogAlertName.
+func WithLogAlertName(name string) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.alertName = name
+	}
+}
+
+// WithAlertRateLimit limits the rate of alerts sent to limit per second with the given burst.
+// Alerts exceeding the rate limit are dropped. Defaults to 1 alert per second with a burst of 10.
+func WithAlertRateLimit(limit float64, burst int) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.async.limit = rate.Limit(limit)
+		c.async.burst = burst
+	}
+}
+
+// WithAlertQueueSize sets the number of alerts buffered for delivery. Alerts are dropped when the
+// queue is full. Defaults to DefaultQueueSize.
+func WithAlertQueueSize(size int) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.async.queueSize = size
+	}
+}
+
+// WithAlertQueueName sets the queue label of the queue metrics, so that several AlertHandlers
+// sharing the Metrics of an Alertmanager can be told apart. Defaults to DefaultAlertHandlerQueueName.
+func WithAlertQueueName(name string) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.async.queueName = name
+	}
+}
+
+// WithAlertSendTimeout sets the timeout for delivering a batch of alerts. Defaults to DefaultSendTimeout.
+func WithAlertSendTimeout(timeout time.Duration) AlertHandlerOption {
+	return func(c *alertHandlerConfig) {
+		c.async.sendTimeout = timeout
+	}
+}
+
+// NewAlertHandler creates an AlertHandler that forwards records to next and sends matching records
+// to Alertmanager using am.
+func NewAlertHandler(next slog.Handler, am Emitter, options ...AlertHandlerOption) *AlertHandler {
+	cfg := &alertHandlerConfig{
+		async:     defaultAsyncConfig(DefaultAlertHandlerQueueName),
+		level:     slog.LevelError,
+		markerKey: DefaultAlertMarkerKey,
+		alertName: DefaultLogAlertName,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	return &AlertHandler{
+		next:    next,
+		emitter: newAsyncEmitter(am, cfg.async),
+		cfg:     cfg,
+	}
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *AlertHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.cfg.level.Level() || h.next.Enabled(ctx, level)
+}
+
+// Handle sends the record to Alertmanager if it matches and forwards it to the next handler.
+func (h *AlertHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.matches(ctx, record) {
+		h.emitter.enqueue(h.alert(record))
+	}
+
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler whose alerts and forwarded records include the given attributes.
+func (h *AlertHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	c := *h
+	c.next = h.next.WithAttrs(attrs)
+	c.attrs = slices.Clip(h.attrs)
+	for _, attr := range attrs {
+		c.attrs = append(c.attrs, qualifyAttr(h.groups, attr))
+	}
+	return &c
+}
+
+// WithGroup returns a handler that qualifies subsequent attributes with the given group name.
+func (h *AlertHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	c := *h
+	c.next = h.next.WithGroup(name)
+	c.groups = append(slices.Clip(h.groups), name)
+	return &c
+}
+
+// Close stops sending alerts and waits until all queued alerts have been delivered or ctx is done.
+// Records handled after Close are still forwarded to the next handler.
+func (h *AlertHandler) Close(ctx context.Context) error {
+	return h.emitter.close(ctx)
+}
+
+// matches reports whether the record should be sent as an alert.
+func (h *AlertHandler) matches(ctx context.Context, record slog.Record) bool {
+	if record.Level < h.cfg.level.Level() {
+		return false
+	}
+	if h.cfg.filter != nil && !h.cfg.filter(ctx, record) {
+		return false
+	}
+	if h.cfg.markerKey == "" {
+		return true
+	}
+
+	// the marker is matched on its own key, since WithGroup qualifies the keys of later attributes
+	marked := false
+	h.eachAttr(record, func(_, leaf string, value slog.Value) bool {
+		if leaf == h.cfg.markerKey {
+			marked = value.Kind() == slog.KindBool && value.Bool()
+			return false
+		}
+		return true
+	})
+	return marked
+}
+
+// alert converts a record into an Alert.
+func (h *AlertHandler) alert(record slog.Record) *Alert {
+	alert := NewAlert(
+		WithLabel("alertname", h.cfg.alertName),
+		WithLabel("level", strings.ToLower(record.Level.String())),
+		WithAnnotation(SummaryAnnotation, record.Message),
+	)
+	if !record.Time.IsZero() {
+		alert.StartsAt = &record.Time
+	}
+
+	h.eachAttr(record, func(key, leaf string, value slog.Value) bool {
+		switch {
+		case leaf == h.cfg.markerKey:
+		case slices.Contains(h.cfg.labelAttrs, key):
+			alert.Labels[key] = value.String()
+		default:
+			alert.Annotations[key] = value.String()
+		}
+		return true
+	})
+
+	return alert
+}
+
+// eachAttr calls f with the dotted key, the unqualified key and the resolved value of every attribute
+// of the handler and the record, until f returns false.
+func (h *AlertHandler) eachAttr(record slog.Record, f func(key, leaf string, value slog.Value) bool) {
+	for _, attr := range h.attrs {
+		if !eachLeafAttr("", attr, f) {
+			return
+		}
+	}
+
+	prefix := strings.Join(h.groups, ".")
+	record.Attrs(func(attr slog.Attr) bool {
+		return eachLeafAttr(prefix, attr, f)
+	})
+}
+
+// eachLeafAttr flattens group attributes into dotted keys.
+func eachLeafAttr(prefix string, attr slog.Attr, f func(key, leaf string, value slog.Value) bool) bool {
+	value := attr.Value.Resolve()
+	key := attr.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	} else if key == "" {
+		key = prefix
+	}
+
+	if value.Kind() != slog.KindGroup {
+		if attr.Key == "" {
+			return true
+		}
+		return f(key, attr.Key, value)
+	}
+
+	for _, child := range value.Group() {
+		if !eachLeafAttr(key, child, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// qualifyAttr nests attr in the given groups.
+func qualifyAttr(groups []string, attr slog.Attr) slog.Attr {
+	for i := len(groups) - 1; i >= 0; i-- {
+		attr = slog.Attr{Key: groups[i], Value: slog.GroupValue(attr)}
+	}
+	return attr
+}
@@ -0,0 +1,242 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// alertRecorder is an Alertmanager test server that records all received alerts.
+type alertRecorder struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func newAlertRecorder(t *testing.T) *alertRecorder {
+	r := &alertRecorder{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var alerts []Alert
+		if err := json.NewDecoder(req.Body).Decode(&alerts); err != nil {
+			t.Errorf("failed to decode alerts: %v", err)
+		}
+		r.mu.Lock()
+		r.alerts = append(r.alerts, alerts...)
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *alertRecorder) received() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
+
+func TestAlertHandler(t *testing.T) {
+	tests := []struct {
+		name                string
+		options             []AlertHandlerOption
+		log                 func(logger *slog.Logger)
+		expectedAlerts      int
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "error with marker",
+			options: []AlertHandlerOption{
+				WithLabelAttrs("component"),
+			},
+			log: func(logger *slog.Logger) {
+				logger.Error("database unreachable", "alert", true, "component", "db", "attempts", 3)
+			},
+			expectedAlerts: 1,
+			expectedLabels: map[string]string{
+				"alertname": DefaultLogAlertName,
+				"level":     "error",
+				"component": "db",
+			},
+			expectedAnnotations: map[string]string{
+				SummaryAnnotation: "database unreachable",
+				"attempts":        "3",
+			},
+		},
+		{
+			name: "error without marker",
+			log: func(logger *slog.Logger) {
+				logger.Error("database unreachable")
+			},
+		},
+		{
+			name: "info with marker",
+			log: func(logger *slog.Logger) {
+				logger.Info("started", "alert", true)
+			},
+		},
+		{
+			name: "no marker and custom level",
+			options: []AlertHandlerOption{
+				WithAlertMarker(""),
+				WithAlertLevel(slog.LevelWarn),
+				WithLogAlertName("Warning"),
+			},
+			log: func(logger *slog.Logger) {
+				logger.Warn("disk almost full")
+				logger.Info("started")
+			},
+			expectedAlerts: 1,
+			expectedLabels: map[string]string{
+				"alertname": "Warning",
+				"level":     "warn",
+			},
+			expectedAnnotations: map[string]string{
+				SummaryAnnotation: "disk almost full",
+			},
+		},
+		{
+			name: "attributes and groups",
+			options: []AlertHandlerOption{
+				WithLabelAttrs("service", "request.method"),
+			},
+			log: func(logger *slog.Logger) {
+				logger.With("service", "api", "alert", true).WithGroup("request").Error("request failed",
+					"method", "GET", "path", "/users")
+			},
+			expectedAlerts: 1,
+			expectedLabels: map[string]string{
+				"alertname":      DefaultLogAlertName,
+				"level":          "error",
+				"service":        "api",
+				"request.method": "GET",
+			},
+			expectedAnnotations: map[string]string{
+				SummaryAnnotation: "request failed",
+				"request.path":    "/users",
+			},
+		},
+		{
+			name: "marker after group",
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("request").Error("request failed", "alert", true, "path", "/users")
+			},
+			expectedAlerts: 1,
+			expectedLabels: map[string]string{
+				"alertname": DefaultLogAlertName,
+				"level":     "error",
+			},
+			expectedAnnotations: map[string]string{
+				SummaryAnnotation: "request failed",
+				"request.path":    "/users",
+			},
+		},
+		{
+			name: "filter",
+			options: []AlertHandlerOption{
+				WithAlertMarker(""),
+				WithAlertFilter(func(_ context.Context, r slog.Record) bool {
+					return r.Message == "page me"
+				}),
+			},
+			log: func(logger *slog.Logger) {
+				logger.Error("ignore me")
+				logger.Error("page me")
+			},
+			expectedAlerts: 1,
+		},
+		{
+			name: "rate limited",
+			options: []AlertHandlerOption{
+				WithAlertMarker(""),
+				WithAlertRateLimit(0, 2),
+			},
+			log: func(logger *slog.Logger) {
+				for range 5 {
+					logger.Error("flapping")
+				}
+			},
+			expectedAlerts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newAlertRecorder(t)
+			am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(recorder.URL))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			var buf bytes.Buffer
+			handler := NewAlertHandler(slog.NewTextHandler(&buf, nil), am, tt.options...)
+			tt.log(slog.New(handler))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := handler.Close(ctx); err != nil {
+				t.Fatalf("failed to close handler: %v", err)
+			}
+
+			if buf.Len() == 0 {
+				t.Errorf("expected records to be forwarded to the next handler")
+			}
+
+			alerts := recorder.received()
+			if len(alerts) != tt.expectedAlerts {
+				t.Fatalf("expected %d alerts, got %d", tt.expectedAlerts, len(alerts))
+			}
+			if tt.expectedLabels != nil && !maps.Equal(alerts[0].Labels, tt.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tt.expectedLabels, alerts[0].Labels)
+			}
+			if tt.expectedAnnotations != nil && !maps.Equal(alerts[0].Annotations, tt.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tt.expectedAnnotations, alerts[0].Annotations)
+			}
+		})
+	}
+}
+
+func TestAlertHandlerDoesNotBlock(t *testing.T) {
+	blocked := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-blocked
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(blocked)
+
+	am, err := NewAlertmanager(logr.Discard(), &http.Client{}, WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create alertmanager: %v", err)
+	}
+
+	handler := NewAlertHandler(slog.DiscardHandler, am,
+		WithAlertMarker(""),
+		WithAlertQueueSize(1),
+		WithAlertRateLimit(1000, 1000))
+	logger := slog.New(handler)
+
+	done := make(chan struct{})
+	go func() {
+		for range 100 {
+			logger.Error("boom")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected logging not to block on a slow Alertmanager")
+	}
+}
@@ -0,0 +1,342 @@
+package alertmanager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// templateDefs defines the $labels and $value variables available in templates, as in Prometheus.
+const templateDefs = "{{$labels := .Labels}}{{$value := .Value}}"
+
+// ErrInvalidTemplate is returned when a label or annotation template cannot be parsed.
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// templateFuncs are the helper functions available in templates, as in Prometheus.
+var templateFuncs = template.FuncMap{
+	"humanize":           humanize,
+	"humanize1024":       humanize1024,
+	"humanizeDuration":   humanizeDuration,
+	"humanizePercentage": humanizePercentage,
+	"humanizeTimestamp":  humanizeTimestamp,
+	"title":              title,
+	"toUpper":            strings.ToUpper,
+	"toLower":            strings.ToLower,
+	"match":              regexp.MatchString,
+	"reReplaceAll":       reReplaceAll,
+}
+
+// templateData is the data templates are executed with.
+type templateData struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// WithLabelTemplate adds a label to an Alert whose value is a Go text/template, rendered when the
+// alert is emitted. Templates can use $labels, $value and Prometheus' helper functions such as
+// humanize, humanizeDuration, title and reReplaceAll. Label templates see the labels of the alert
+// merged with the base labels, and take precedence over plain labels with the same key.
+func WithLabelTemplate(key, text string) AlertOption {
+	return func(a *Alert) {
+		if a.labelTemplates == nil {
+			a.labelTemplates = make(map[string]string)
+		}
+		a.labelTemplates[key] = text
+	}
+}
+
+// WithAnnotationTemplate adds an annotation to an Alert whose value is a Go text/template, rendered
+// when the alert is emitted, e.g. "{{ $labels.instance }} is down ({{ $value | humanize }})".
+// Annotation templates see the final labels of the alert, including rendered label templates.
+func WithAnnotationTemplate(key, text string) AlertOption {
+	return func(a *Alert) {
+		if a.annotationTemplates == nil {
+			a.annotationTemplates = make(map[string]string)
+		}
+		a.annotationTemplates[key] = text
+	}
+}
+
+// WithValue sets the value available as $value in the templates of an Alert.
+func WithValue(value float64) AlertOption {
+	return func(a *Alert) {
+		a.value = value
+	}
+}
+
+// RenderTemplates returns a copy of the Alert whose label and annotation templates are rendered into
+// its labels and annotations, as Emit does before sending it. Base labels and annotations of an
+// Alertmanager are not applied. It is intended for Emitter implementations that do not send alerts,
+// such as test recorders.
+func (a *Alert) RenderTemplates() (*Alert, error) {
+	c := &Alert{
+		Labels:      maps.Clone(a.Labels),
+		Annotations: maps.Clone(a.Annotations),
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+	}
+	if len(a.labelTemplates) > 0 && c.Labels == nil {
+		c.Labels = make(map[string]string)
+	}
+	if len(a.annotationTemplates) > 0 && c.Annotations == nil {
+		c.Annotations = make(map[string]string)
+	}
+
+	if err := a.renderTemplates(c.Labels, c.Annotations); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// renderTemplates renders the label and annotation templates of the alert into labels and annotations.
+func (a *Alert) renderTemplates(labels, annotations map[string]string) error {
+	if len(a.labelTemplates) == 0 && len(a.annotationTemplates) == 0 {
+		return nil
+	}
+
+	rendered := make(map[string]string, len(a.labelTemplates))
+	data := templateData{Labels: maps.Clone(labels), Value: a.value}
+	for k, text := range a.labelTemplates {
+		value, err := renderTemplate("label "+k, text, data)
+		if err != nil {
+			return err
+		}
+		rendered[k] = value
+	}
+	maps.Copy(labels, rendered)
+
+	data.Labels = labels
+	for k, text := range a.annotationTemplates {
+		value, err := renderTemplate("annotation "+k, text, data)
+		if err != nil {
+			return err
+		}
+		annotations[k] = value
+	}
+	return nil
+}
+
+func renderTemplate(name, text string, data templateData) (string, error) {
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	return executeTemplate(tmpl, data)
+}
+
+// parseTemplate parses a label or annotation template.
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Funcs(templateFuncs).Parse(templateDefs + text)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidTemplate, name, err)
+	}
+	return tmpl, nil
+}
+
+// parseTemplates parses a template for every value of m.
+func parseTemplates(kind string, m map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(m))
+	for k, text := range m {
+		tmpl, err := parseTemplate(kind+" "+k, text)
+		if err != nil {
+			return nil, err
+		}
+		templates[k] = tmpl
+	}
+	return templates, nil
+}
+
+// executeTemplate executes a template with the given data.
+func executeTemplate(tmpl *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to expand template %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// expandTemplates executes every template, writing the results to dst.
+// A template that fails to expand results in an error message, as in Prometheus.
+func expandTemplates(dst map[string]string, templates map[string]*template.Template, data templateData) {
+	for k, tmpl := range templates {
+		value, err := executeTemplate(tmpl, data)
+		if err != nil {
+			value = fmt.Sprintf("<error expanding template: %v>", err)
+		}
+		dst[k] = value
+	}
+}
+
+// toFloat64 converts a template argument to a float64. Strings are parsed, e.g. label values.
+func toFloat64(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case time.Duration:
+		return v.Seconds(), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("can't convert %T to float", v)
+	}
+}
+
+// humanize formats a number with a metric prefix, e.g. 1234567 as 1.235M.
+func humanize(v any) (string, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return "", err
+	}
+	if f == 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprintf("%.4g", f), nil
+	}
+
+	prefix := ""
+	if math.Abs(f) >= 1 {
+		for _, p := range []string{"k", "M", "G", "T", "P", "E", "Z", "Y"} {
+			if math.Abs(f) < 1000 {
+				break
+			}
+			prefix = p
+			f /= 1000
+		}
+		return fmt.Sprintf("%.4g%s", f, prefix), nil
+	}
+	for _, p := range []string{"m", "u", "n", "p", "f", "a", "z", "y"} {
+		if math.Abs(f) >= 1 {
+			break
+		}
+		prefix = p
+		f *= 1000
+	}
+	return fmt.Sprintf("%.4g%s", f, prefix), nil
+}
+
+// humanize1024 formats a number with a binary prefix, e.g. 1048576 as 1Mi.
+func humanize1024(v any) (string, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return "", err
+	}
+	if math.Abs(f) <= 1 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprintf("%.4g", f), nil
+	}
+
+	prefix := ""
+	for _, p := range []string{"ki", "Mi", "Gi", "Ti", "Pi", "Ei", "Zi", "Yi"} {
+		if math.Abs(f) < 1024 {
+			break
+		}
+		prefix = p
+		f /= 1024
+	}
+	return fmt.Sprintf("%.4g%s", f, prefix), nil
+}
+
+// humanizeDuration formats a number of seconds as a duration, e.g. 3725 as 1h 2m 5s.
+func humanizeDuration(v any) (string, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return "", err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprintf("%.4g", f), nil
+	}
+	if f == 0 {
+		return "0s", nil
+	}
+
+	if math.Abs(f) >= 1 {
+		sign := ""
+		if f < 0 {
+			sign = "-"
+			f = -f
+		}
+		duration := int64(f)
+		seconds := duration % 60
+		minutes := (duration / 60) % 60
+		hours := (duration / 60 / 60) % 24
+		days := duration / 60 / 60 / 24
+		switch {
+		case days != 0:
+			return fmt.Sprintf("%s%dd %dh %dm %ds", sign, days, hours, minutes, seconds), nil
+		case hours != 0:
+			return fmt.Sprintf("%s%dh %dm %ds", sign, hours, minutes, seconds), nil
+		case minutes != 0:
+			return fmt.Sprintf("%s%dm %ds", sign, minutes, seconds), nil
+		}
+		return fmt.Sprintf("%s%.4gs", sign, f), nil
+	}
+
+	prefix := ""
+	for _, p := range []string{"m", "u", "n", "p", "f", "a", "z", "y"} {
+		if math.Abs(f) >= 1 {
+			break
+		}
+		prefix = p
+		f *= 1000
+	}
+	return fmt.Sprintf("%.4g%ss", f, prefix), nil
+}
+
+// humanizePercentage formats a ratio as a percentage, e.g. 0.1234 as 12.34%.
+func humanizePercentage(v any) (string, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%.4g%%", f*100), nil
+}
+
+// humanizeTimestamp formats a Unix timestamp in seconds as a UTC time.
+func humanizeTimestamp(v any) (string, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return "", err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprintf("%.4g", f), nil
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC().String(), nil
+}
+
+// title converts the first letter of every word to title case, leaving other letters unchanged.
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		wordStart := !unicode.IsLetter(prev) && !unicode.IsDigit(prev) && prev != '\''
+		prev = r
+		if wordStart {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// reReplaceAll replaces all matches of the regular expression pattern in text with repl.
+func reReplaceAll(pattern, repl, text string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(text, repl), nil
+}
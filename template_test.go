@@ -0,0 +1,152 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		value    float64
+		labels   map[string]string
+		expected string
+	}{
+		{name: "humanize large", text: "{{ humanize $value }}", value: 1234567, expected: "1.235M"},
+		{name: "humanize small", text: "{{ humanize $value }}", value: 0.0012, expected: "1.2m"},
+		{name: "humanize zero", text: "{{ humanize $value }}", value: 0, expected: "0"},
+		{name: "humanize label", text: `{{ humanize $labels.bytes }}`, labels: map[string]string{"bytes": "2500"}, expected: "2.5k"},
+		{name: "humanize1024", text: "{{ humanize1024 $value }}", value: 1048576, expected: "1Mi"},
+		{name: "humanizeDuration", text: "{{ humanizeDuration $value }}", value: 3725, expected: "1h 2m 5s"},
+		{name: "humanizeDuration days", text: "{{ humanizeDuration $value }}", value: 90061, expected: "1d 1h 1m 1s"},
+		{name: "humanizeDuration seconds", text: "{{ humanizeDuration $value }}", value: 1.5, expected: "1.5s"},
+		{name: "humanizeDuration milliseconds", text: "{{ humanizeDuration $value }}", value: 0.25, expected: "250ms"},
+		{name: "humanizePercentage", text: "{{ humanizePercentage $value }}", value: 0.1234, expected: "12.34%"},
+		{name: "humanizeTimestamp", text: "{{ humanizeTimestamp $value }}", value: 1700000000, expected: "2023-11-14 22:13:20 +0000 UTC"},
+		{name: "humanize NaN", text: "{{ humanize $value }}", value: math.NaN(), expected: "NaN"},
+		{name: "title", text: `{{ title "disk almost full" }}`, expected: "Disk Almost Full"},
+		{name: "toUpper and toLower", text: `{{ toUpper "a" }}{{ toLower "B" }}`, expected: "Ab"},
+		{name: "match", text: `{{ if match "^db-" $labels.instance }}database{{ end }}`, labels: map[string]string{"instance": "db-1"}, expected: "database"},
+		{name: "reReplaceAll", text: `{{ reReplaceAll ":[0-9]+$" "" $labels.instance }}`, labels: map[string]string{"instance": "host:9100"}, expected: "host"},
+		{name: "missing label", text: "{{ $labels.missing }}", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderTemplate("test", tt.text, templateData{Labels: tt.labels, Value: tt.value})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAlertTemplates(t *testing.T) {
+	tests := []struct {
+		name                string
+		options             []AlertOption
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+		expectedErr         error
+		expectedErrContains string
+	}{
+		{
+			name: "annotation templates",
+			options: []AlertOption{
+				WithLabel("instance", "web-1:8080"),
+				WithValue(0.375),
+				WithAnnotationTemplate("description", "{{ $labels.instance }} is down ({{ $value | humanizePercentage }}) in {{ $labels.cluster }}"),
+			},
+			expectedLabels: map[string]string{"alertname": "Down", "instance": "web-1:8080", "cluster": "prod"},
+			expectedAnnotations: map[string]string{
+				"description": "web-1:8080 is down (37.5%) in prod",
+			},
+		},
+		{
+			name: "label templates are rendered before annotation templates",
+			options: []AlertOption{
+				WithLabel("instance", "web-1:8080"),
+				WithLabelTemplate("host", `{{ reReplaceAll ":[0-9]+$" "" $labels.instance }}`),
+				WithAnnotationTemplate("summary", "{{ $labels.host | title }} is down"),
+			},
+			expectedLabels: map[string]string{"alertname": "Down", "instance": "web-1:8080", "cluster": "prod", "host": "web-1"},
+			expectedAnnotations: map[string]string{
+				"summary": "Web-1 is down",
+			},
+		},
+		{
+			name: "invalid template",
+			options: []AlertOption{
+				WithAnnotationTemplate("summary", "{{ $labels.instance"),
+			},
+			expectedErr:         ErrInvalidTemplate,
+			expectedErrContains: "annotation summary",
+		},
+		{
+			name: "template execution error",
+			options: []AlertOption{
+				WithLabel("bytes", "lots"),
+				WithAnnotationTemplate("summary", "{{ humanize $labels.bytes }}"),
+			},
+			expectedErrContains: "failed to expand template annotation summary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newAlertRecorder(t)
+			am, err := NewAlertmanager(logr.Discard(), &http.Client{},
+				WithEndpoint(recorder.URL),
+				WithBaseLabel("cluster", "prod"),
+			)
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			alert := NewAlert(append([]AlertOption{WithLabel("alertname", "Down"), WithStartsAt(time.Now())}, tt.options...)...)
+			resp, err := am.EmitContext(context.Background(), alert)
+			if tt.expectedErrContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErrContains) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErrContains, err)
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				if len(recorder.received()) != 0 {
+					t.Error("expected no alerts to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			resp.Body.Close()
+
+			alerts := recorder.received()
+			if len(alerts) != 1 {
+				t.Fatalf("expected 1 alert, got %d", len(alerts))
+			}
+			for k, v := range tt.expectedLabels {
+				if alerts[0].Labels[k] != v {
+					t.Errorf("expected label %s=%q, got %q", k, v, alerts[0].Labels[k])
+				}
+			}
+			for k, v := range tt.expectedAnnotations {
+				if alerts[0].Annotations[k] != v {
+					t.Errorf("expected annotation %s=%q, got %q", k, v, alerts[0].Annotations[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// DefaultTenantHeader is the HTTP header used by Mimir and Cortex to identify the tenant of a request.
+	DefaultTenantHeader = "X-Scope-OrgID"
+
+	// MimirPathPrefix is the default path prefix of the Mimir and Cortex Alertmanager API.
+	MimirPathPrefix = "/alertmanager"
+)
+
+// ErrTenantRequired is returned when a tenant-aware request is made without a tenant ID.
+var ErrTenantRequired = errors.New("tenant ID required")
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx that carries the given tenant ID.
+// The tenant ID is sent with every request made using the returned context.
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID carried by ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// WithTenant sets the tenant ID sent with every request.
+// A tenant ID carried by the request context takes precedence.
+func WithTenant(tenantID string) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.tenantID = tenantID
+		return nil
+	}
+}
+
+// WithTenantHeader overrides the HTTP header used to send the tenant ID.
+// If not specified, DefaultTenantHeader is used.
+func WithTenantHeader(header string) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.tenantHeader = http.CanonicalHeaderKey(header)
+		return nil
+	}
+}
+
+// WithMimirPathPrefix prepends MimirPathPrefix to all Alertmanager API paths,
+// as expected by the Mimir and Cortex Alertmanager. It is equivalent to WithPathPrefix(MimirPathPrefix).
+func WithMimirPathPrefix() ManagerOption {
+	return WithPathPrefix(MimirPathPrefix)
+}
+
+// tenant returns the tenant ID for a request made with ctx.
+func (a *Alertmanager) tenant(ctx context.Context) string {
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		return tenantID
+	}
+	return a.tenantID
+}
+
+func (a *Alertmanager) tenantHeaderName() string {
+	if a.tenantHeader == "" {
+		return DefaultTenantHeader
+	}
+	return a.tenantHeader
+}
+
+// TenantClient sends alerts on behalf of many tenants of a multi-tenant Alertmanager
+// such as Mimir or Cortex. All tenants share a single HTTP client and its connection pool.
+type TenantClient struct {
+	base *Alertmanager
+
+	mu      sync.RWMutex
+	labels  map[string]map[string]string
+	tenants map[string]*Alertmanager
+}
+
+// NewTenantClient creates a new TenantClient with the provided logger, HTTP client, and options.
+// The options apply to all tenants. Use WithMimirPathPrefix to target the Mimir API.
+func NewTenantClient(logger logr.Logger, client *http.Client, options ...ManagerOption) (*TenantClient, error) {
+	base, err := NewAlertmanager(logger, client, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantClient{
+		base:    base,
+		labels:  make(map[string]map[string]string),
+		tenants: make(map[string]*Alertmanager),
+	}, nil
+}
+
+// SetTenantLabels sets base labels that are applied to all alerts sent for the given tenant,
+// in addition to the base labels of the client. It replaces any labels previously set for the tenant.
+func (t *TenantClient) SetTenantLabels(tenantID string, labels map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.labels[tenantID] = maps.Clone(labels)
+	delete(t.tenants, tenantID)
+}
+
+// Tenant returns an Alertmanager that sends alerts for the given tenant.
+// The returned Alertmanager shares the HTTP client of the TenantClient.
+func (t *TenantClient) Tenant(tenantID string) *Alertmanager {
+	t.mu.RLock()
+	am, ok := t.tenants[tenantID]
+	t.mu.RUnlock()
+	if ok {
+		return am
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if am, ok := t.tenants[tenantID]; ok {
+		return am
+	}
+
+	am = t.base.clone()
+	am.tenantID = tenantID
+	maps.Copy(am.labels, t.labels[tenantID])
+	t.tenants[tenantID] = am
+
+	return am
+}
+
+// EmitContext sends one or more alerts for the tenant carried by ctx (see ContextWithTenant).
+// Returns ErrTenantRequired if ctx does not carry a tenant ID.
+func (t *TenantClient) EmitContext(ctx context.Context, alerts ...*Alert) (*http.Response, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrTenantRequired
+	}
+	return t.Tenant(tenantID).EmitContext(ctx, alerts...)
+}
+
+// EmitForTenant sends one or more alerts for the given tenant.
+func (t *TenantClient) EmitForTenant(ctx context.Context, tenantID string, alerts ...*Alert) (*http.Response, error) {
+	if tenantID == "" {
+		return nil, ErrTenantRequired
+	}
+	return t.Tenant(tenantID).EmitContext(ContextWithTenant(ctx, tenantID), alerts...)
+}
@@ -0,0 +1,174 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestTenantClient(t *testing.T) {
+	logger := logr.Discard()
+
+	tests := []struct {
+		name           string
+		options        []ManagerOption
+		tenantLabels   map[string]map[string]string
+		emit           func(tc *TenantClient) (*http.Response, error)
+		expectedErr    error
+		expectedHeader string
+		expectedTenant string
+		expectedPath   string
+		expectedLabels map[string]string
+	}{
+		{
+			name: "tenant from context",
+			emit: func(tc *TenantClient) (*http.Response, error) {
+				ctx := ContextWithTenant(context.Background(), "team-a")
+				return tc.EmitContext(ctx, NewAlert(WithLabel("alertname", "test")))
+			},
+			expectedHeader: DefaultTenantHeader,
+			expectedTenant: "team-a",
+			expectedPath:   "/api/v2/alerts",
+			expectedLabels: map[string]string{"alertname": "test"},
+		},
+		{
+			name: "explicit tenant overrides context",
+			emit: func(tc *TenantClient) (*http.Response, error) {
+				ctx := ContextWithTenant(context.Background(), "team-a")
+				return tc.EmitForTenant(ctx, "team-b", NewAlert(WithLabel("alertname", "test")))
+			},
+			expectedHeader: DefaultTenantHeader,
+			expectedTenant: "team-b",
+			expectedPath:   "/api/v2/alerts",
+			expectedLabels: map[string]string{"alertname": "test"},
+		},
+		{
+			name: "mimir path prefix and custom header",
+			options: []ManagerOption{
+				WithMimirPathPrefix(),
+				WithTenantHeader("x-tenant"),
+			},
+			emit: func(tc *TenantClient) (*http.Response, error) {
+				return tc.EmitForTenant(context.Background(), "team-a", NewAlert(WithLabel("alertname", "test")))
+			},
+			expectedHeader: "X-Tenant",
+			expectedTenant: "team-a",
+			expectedPath:   "/alertmanager/api/v2/alerts",
+			expectedLabels: map[string]string{"alertname": "test"},
+		},
+		{
+			name: "per-tenant base labels",
+			options: []ManagerOption{
+				WithBaseLabel("service", "test-service"),
+			},
+			tenantLabels: map[string]map[string]string{
+				"team-a": {"team": "a"},
+				"team-b": {"team": "b"},
+			},
+			emit: func(tc *TenantClient) (*http.Response, error) {
+				return tc.EmitForTenant(context.Background(), "team-a", NewAlert(WithLabel("alertname", "test")))
+			},
+			expectedHeader: DefaultTenantHeader,
+			expectedTenant: "team-a",
+			expectedPath:   "/api/v2/alerts",
+			expectedLabels: map[string]string{"alertname": "test", "service": "test-service", "team": "a"},
+		},
+		{
+			name: "missing tenant",
+			emit: func(tc *TenantClient) (*http.Response, error) {
+				return tc.EmitContext(context.Background(), NewAlert(WithLabel("alertname", "test")))
+			},
+			expectedErr: ErrTenantRequired,
+		},
+		{
+			name: "empty explicit tenant",
+			emit: func(tc *TenantClient) (*http.Response, error) {
+				return tc.EmitForTenant(context.Background(), "", NewAlert(WithLabel("alertname", "test")))
+			},
+			expectedErr: ErrTenantRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTenant, gotPath string
+			var gotAlerts []Alert
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant = r.Header.Get(tt.expectedHeader)
+				gotPath = r.URL.Path
+				if err := json.NewDecoder(r.Body).Decode(&gotAlerts); err != nil {
+					t.Errorf("failed to decode alerts: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			options := append([]ManagerOption{WithEndpoint(server.URL)}, tt.options...)
+			tc, err := NewTenantClient(logger, &http.Client{}, options...)
+			if err != nil {
+				t.Fatalf("failed to create tenant client: %v", err)
+			}
+			for tenantID, labels := range tt.tenantLabels {
+				tc.SetTenantLabels(tenantID, labels)
+			}
+
+			resp, err := tt.emit(tc)
+			if resp != nil {
+				defer resp.Body.Close()
+			}
+
+			if tt.expectedErr != nil {
+				if err != tt.expectedErr {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotTenant != tt.expectedTenant {
+				t.Errorf("expected tenant %q, got %q", tt.expectedTenant, gotTenant)
+			}
+			if gotPath != tt.expectedPath {
+				t.Errorf("expected path %q, got %q", tt.expectedPath, gotPath)
+			}
+			if len(gotAlerts) != 1 {
+				t.Fatalf("expected 1 alert, got %d", len(gotAlerts))
+			}
+			if len(gotAlerts[0].Labels) != len(tt.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tt.expectedLabels, gotAlerts[0].Labels)
+			}
+			for k, v := range tt.expectedLabels {
+				if gotAlerts[0].Labels[k] != v {
+					t.Errorf("expected label %s=%s, got %s", k, v, gotAlerts[0].Labels[k])
+				}
+			}
+		})
+	}
+}
+
+func TestTenantClientSharesHTTPClient(t *testing.T) {
+	client := &http.Client{}
+	tc, err := NewTenantClient(logr.Discard(), client, WithEndpoint("http://alertmanager:9093"))
+	if err != nil {
+		t.Fatalf("failed to create tenant client: %v", err)
+	}
+
+	a, b := tc.Tenant("team-a"), tc.Tenant("team-b")
+	if a.client != tc.base.client || b.client != tc.base.client {
+		t.Errorf("expected tenants to share the HTTP client")
+	}
+	if tc.Tenant("team-a") != a {
+		t.Errorf("expected tenant Alertmanager to be reused")
+	}
+
+	a.labels["only"] = "a"
+	if _, ok := b.labels["only"]; ok {
+		t.Errorf("expected tenants to own their base labels")
+	}
+}
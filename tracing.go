@@ -0,0 +1,155 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the instrumentation scope name of the spans created by the Alertmanager client.
+	tracerName = "github.com/spectrocloud-labs/alertmanager-client-go"
+
+	// DefaultTraceIDKey is the default label or annotation key used to link an alert to a trace.
+	DefaultTraceIDKey = "trace_id"
+
+	// DefaultSpanIDKey is the default label or annotation key used to link an alert to a span.
+	DefaultSpanIDKey = "span_id"
+)
+
+// alertCountKey is the span attribute holding the number of alerts in a request.
+var alertCountKey = attribute.Key("alertmanager.alert_count")
+
+// traceLink copies the trace ID and span ID of a context into labels or annotations.
+// Empty keys are skipped.
+type traceLink struct {
+	traceIDKey string
+	spanIDKey  string
+}
+
+// apply copies the IDs of the span in ctx into m without overwriting existing values.
+func (l traceLink) apply(ctx context.Context, m map[string]string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	if _, ok := m[l.traceIDKey]; l.traceIDKey != "" && !ok {
+		m[l.traceIDKey] = sc.TraceID().String()
+	}
+	if _, ok := m[l.spanIDKey]; l.spanIDKey != "" && !ok {
+		m[l.spanIDKey] = sc.SpanID().String()
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry TracerProvider used to create a span for every request.
+// If not specified, the global TracerProvider is used.
+func WithTracerProvider(tp trace.TracerProvider) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.tracerProvider = tp
+		return nil
+	}
+}
+
+// WithPropagator sets the OpenTelemetry propagator used to inject the trace context into request headers.
+// If not specified, the global TextMapPropagator is used.
+func WithPropagator(p propagation.TextMapPropagator) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.propagator = p
+		return nil
+	}
+}
+
+// WithTraceLinkAnnotations copies the trace ID and span ID of the context passed to EmitContext
+// into the given annotations of every alert, e.g. DefaultTraceIDKey and DefaultSpanIDKey.
+// Annotations already set on an alert are not overwritten. An empty key is skipped.
+func WithTraceLinkAnnotations(traceIDKey, spanIDKey string) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.traceAnnotations = &traceLink{traceIDKey: traceIDKey, spanIDKey: spanIDKey}
+		return nil
+	}
+}
+
+// WithTraceLinkLabels copies the trace ID and span ID of the context passed to EmitContext
+// into the given labels of every alert. Labels already set on an alert are not overwritten.
+// An empty key is skipped. Since labels identify alerts, every trace results in a distinct alert.
+func WithTraceLinkLabels(traceIDKey, spanIDKey string) ManagerOption {
+	return func(a *Alertmanager) error {
+		a.traceLabels = &traceLink{traceIDKey: traceIDKey, spanIDKey: spanIDKey}
+		return nil
+	}
+}
+
+// WithTraceAnnotations copies the trace ID and span ID of the span in ctx into the given annotations
+// of an Alert. An empty key is skipped.
+func WithTraceAnnotations(ctx context.Context, traceIDKey, spanIDKey string) AlertOption {
+	return func(a *Alert) {
+		if a.Annotations == nil {
+			a.Annotations = make(map[string]string)
+		}
+		traceLink{traceIDKey: traceIDKey, spanIDKey: spanIDKey}.apply(ctx, a.Annotations)
+	}
+}
+
+// WithTraceLabels copies the trace ID and span ID of the span in ctx into the given labels
+// of an Alert. An empty key is skipped.
+func WithTraceLabels(ctx context.Context, traceIDKey, spanIDKey string) AlertOption {
+	return func(a *Alert) {
+		if a.Labels == nil {
+			a.Labels = make(map[string]string)
+		}
+		traceLink{traceIDKey: traceIDKey, spanIDKey: spanIDKey}.apply(ctx, a.Labels)
+	}
+}
+
+// startSpan starts a client span for an API request and injects its context into the request headers.
+func (a *Alertmanager) startSpan(req *http.Request, operation string, attrs ...attribute.KeyValue) (*http.Request, trace.Span) {
+	tp := a.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	// userinfo is never recorded, since spans are exported to a third party
+	u := *req.URL
+	u.User = nil
+
+	ctx, span := tp.Tracer(tracerName).Start(req.Context(), "alertmanager."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(u.String()),
+		),
+		trace.WithAttributes(attrs...),
+	)
+
+	propagator := a.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req.WithContext(ctx), span
+}
+
+// endSpan records the outcome of an API request and ends its span.
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttributes(semconv.ErrorType(err))
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode/100 != 2 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+}
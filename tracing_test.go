@@ -0,0 +1,216 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEmitTracing(t *testing.T) {
+	logger := logr.Discard()
+
+	tests := []struct {
+		name           string
+		userinfo       string
+		serverStatus   int
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "successful emit",
+			serverStatus:   http.StatusOK,
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "server error",
+			serverStatus:   http.StatusInternalServerError,
+			expectedStatus: codes.Error,
+		},
+		{
+			name:           "credentials in endpoint",
+			userinfo:       "admin:s3cret@",
+			serverStatus:   http.StatusOK,
+			expectedStatus: codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTraceparent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTraceparent = r.Header.Get("traceparent")
+				w.WriteHeader(tt.serverStatus)
+			}))
+			defer server.Close()
+
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			am, err := NewAlertmanager(logger, &http.Client{},
+				WithEndpoint(strings.Replace(server.URL, "http://", "http://"+tt.userinfo, 1)),
+				WithTracerProvider(tp),
+				WithPropagator(propagation.TraceContext{}))
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			resp, err := am.Emit(NewAlert(WithLabel("alertname", "a")), NewAlert(WithLabel("alertname", "b")))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+
+			if span.Name() != "alertmanager.Emit" {
+				t.Errorf("expected span name alertmanager.Emit, got %s", span.Name())
+			}
+			if span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("expected client span, got %v", span.SpanKind())
+			}
+			if span.Status().Code != tt.expectedStatus {
+				t.Errorf("expected span status %v, got %v", tt.expectedStatus, span.Status().Code)
+			}
+
+			attrs := make(map[string]any)
+			for _, attr := range span.Attributes() {
+				attrs[string(attr.Key)] = attr.Value.AsInterface()
+			}
+			if attrs["alertmanager.alert_count"] != int64(2) {
+				t.Errorf("expected alert count attribute 2, got %v", attrs["alertmanager.alert_count"])
+			}
+			if attrs["http.response.status_code"] != int64(tt.serverStatus) {
+				t.Errorf("expected status code attribute %d, got %v", tt.serverStatus, attrs["http.response.status_code"])
+			}
+			if expected := server.URL + "/api/v2/alerts"; attrs["url.full"] != expected {
+				t.Errorf("expected url attribute %s, got %v", expected, attrs["url.full"])
+			}
+
+			if gotTraceparent == "" {
+				t.Errorf("expected traceparent header to be propagated")
+			}
+		})
+	}
+}
+
+func TestTraceLinks(t *testing.T) {
+	logger := logr.Discard()
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	tests := []struct {
+		name                string
+		ctx                 context.Context
+		options             []ManagerOption
+		alert               *Alert
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:    "annotations from emit context",
+			ctx:     ctx,
+			options: []ManagerOption{WithTraceLinkAnnotations(DefaultTraceIDKey, DefaultSpanIDKey)},
+			alert:   NewAlert(WithLabel("alertname", "test")),
+			expectedAnnotations: map[string]string{
+				DefaultTraceIDKey: traceID.String(),
+				DefaultSpanIDKey:  spanID.String(),
+			},
+		},
+		{
+			name:    "labels from emit context with trace ID only",
+			ctx:     ctx,
+			options: []ManagerOption{WithTraceLinkLabels("trace", "")},
+			alert:   NewAlert(WithLabel("alertname", "test")),
+			expectedLabels: map[string]string{
+				"alertname": "test",
+				"trace":     traceID.String(),
+			},
+		},
+		{
+			name:    "existing annotation is not overwritten",
+			ctx:     ctx,
+			options: []ManagerOption{WithTraceLinkAnnotations(DefaultTraceIDKey, "")},
+			alert:   NewAlert(WithLabel("alertname", "test"), WithAnnotation(DefaultTraceIDKey, "explicit")),
+			expectedAnnotations: map[string]string{
+				DefaultTraceIDKey: "explicit",
+			},
+		},
+		{
+			name:                "context without span",
+			ctx:                 context.Background(),
+			options:             []ManagerOption{WithTraceLinkAnnotations(DefaultTraceIDKey, DefaultSpanIDKey)},
+			alert:               NewAlert(WithLabel("alertname", "test")),
+			expectedAnnotations: map[string]string{},
+		},
+		{
+			name: "alert options",
+			ctx:  context.Background(),
+			alert: NewAlert(
+				WithTraceAnnotations(ctx, "trace", "span"),
+				WithTraceLabels(ctx, "trace", ""),
+			),
+			expectedLabels: map[string]string{
+				"trace": traceID.String(),
+			},
+			expectedAnnotations: map[string]string{
+				"trace": traceID.String(),
+				"span":  spanID.String(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAlerts []Alert
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&gotAlerts); err != nil {
+					t.Errorf("failed to decode alerts: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			options := append([]ManagerOption{WithEndpoint(server.URL)}, tt.options...)
+			am, err := NewAlertmanager(logger, &http.Client{}, options...)
+			if err != nil {
+				t.Fatalf("failed to create alertmanager: %v", err)
+			}
+
+			resp, err := am.EmitContext(tt.ctx, tt.alert)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if len(gotAlerts) != 1 {
+				t.Fatalf("expected 1 alert, got %d", len(gotAlerts))
+			}
+			if tt.expectedLabels != nil && !maps.Equal(gotAlerts[0].Labels, tt.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tt.expectedLabels, gotAlerts[0].Labels)
+			}
+			if tt.expectedAnnotations != nil && !maps.Equal(gotAlerts[0].Annotations, tt.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tt.expectedAnnotations, gotAlerts[0].Annotations)
+			}
+		})
+	}
+}